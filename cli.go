@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type optionKind int
+
+const (
+	// a flag that doesn't take a value. --draft is the same as --draft=true
+	boolOption optionKind = iota
+	// an option that takes a value as --name=value or --name value
+	valueOption
+)
+
+type option struct {
+	long  string
+	short string
+	kind  optionKind
+	// name of the value in help and error messages. e.g. directory
+	valueName string
+	// shown in error messages when a value is missing
+	example string
+	help    string
+}
+
+func (o option) String() string {
+	if o.short == "" {
+		return "--" + o.long
+	}
+	return "-" + o.short + "/--" + o.long
+}
+
+var helpOption = option{
+	long:  "help",
+	short: "h",
+	kind:  boolOption,
+	help:  "show this help",
+}
+
+type command struct {
+	name    string
+	summary string
+	// names of the positional arguments. all of them are required
+	args    []string
+	options []option
+	// creates an Action from the parsed command line
+	action func(parsed parsedArgs) (Action, error)
+}
+
+type parsedArgs struct {
+	positional []string
+	// raw option values keyed by the option's long name.
+	// bool options are either "true" or "false"
+	values map[string]string
+}
+
+func (p parsedArgs) has(long string) bool {
+	_, ok := p.values[long]
+	return ok
+}
+
+func (p parsedArgs) bool(long string) bool {
+	return p.values[long] == "true"
+}
+
+// the help option is always accepted
+func (c *command) allOptions() []option {
+	return append(c.options[:len(c.options):len(c.options)], helpOption)
+}
+
+func (c *command) findOption(name string) (option, bool) {
+	for _, opt := range c.allOptions() {
+		if name == "--"+opt.long || (opt.short != "" && name == "-"+opt.short) {
+			return opt, true
+		}
+	}
+	return option{}, false
+}
+
+// parse accepts options as --name=value, --name value, -n value and -n=value.
+// options and positional arguments can be mixed. everything after a '--' is
+// treated as a positional argument
+func (c *command) parse(args []string) (parsedArgs, error) {
+	parsed := parsedArgs{values: make(map[string]string)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			parsed.positional = append(parsed.positional, args[i+1:]...)
+			break
+		}
+
+		if len(arg) < 2 || arg[0] != '-' {
+			parsed.positional = append(parsed.positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		opt, ok := c.findOption(name)
+		if !ok {
+			return parsedArgs{}, fmt.Errorf(
+				"unrecognized option: %s. valid options are %s",
+				arg,
+				c.sprintOptionNames(),
+			)
+		}
+
+		if _, ok := parsed.values[opt.long]; ok {
+			return parsedArgs{}, fmt.Errorf(
+				"multiple options given for --%s",
+				opt.long,
+			)
+		}
+
+		switch opt.kind {
+		case boolOption:
+			if !hasValue {
+				value = "true"
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return parsedArgs{}, fmt.Errorf(
+					"invalid value %q for --%s. expected true or false",
+					value,
+					opt.long,
+				)
+			}
+			value = strconv.FormatBool(b)
+
+		case valueOption:
+			if !hasValue {
+				if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+					return parsedArgs{}, fmt.Errorf(
+						"expected a %s for --%s. example --%s=%s",
+						opt.valueName,
+						opt.long,
+						opt.long,
+						opt.example,
+					)
+				}
+				i++
+				value = args[i]
+			}
+		}
+
+		parsed.values[opt.long] = value
+	}
+
+	return parsed, nil
+}
+
+func (c *command) sprintOptionNames() string {
+	opts := c.allOptions()
+	names := make([]string, len(opts))
+	for i, opt := range opts {
+		names[i] = opt.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+func (c *command) sprintUsage() string {
+	var usage strings.Builder
+	usage.WriteString("usage: ssg " + c.name)
+	for _, arg := range c.args {
+		usage.WriteString(" [" + arg + "]")
+	}
+	if len(c.options) > 0 {
+		usage.WriteString(" [options]")
+	}
+	return usage.String()
+}
+
+func (c *command) sprintHelp() string {
+	var help strings.Builder
+	help.WriteString(c.sprintUsage() + "\n\n")
+	help.WriteString(c.summary + "\n\noptions:\n")
+
+	w := tabwriter.NewWriter(&help, 0, 0, 4, ' ', 0)
+	for _, opt := range c.allOptions() {
+		names := "    --" + opt.long
+		if opt.short != "" {
+			names = "-" + opt.short + ", --" + opt.long
+		}
+		if opt.kind == valueOption {
+			names += " <" + opt.valueName + ">"
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", names, opt.help)
+	}
+	w.Flush()
+
+	return strings.TrimSuffix(help.String(), "\n")
+}
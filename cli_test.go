@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandHelp(t *testing.T) {
+	for _, cmd := range commands {
+		t.Run(cmd.name, func(t *testing.T) {
+			help := cmd.sprintHelp()
+
+			if !strings.HasPrefix(help, cmd.sprintUsage()) {
+				t.Errorf("help doesn't start with the usage. got=\n%s", help)
+			}
+
+			for _, opt := range cmd.allOptions() {
+				if !strings.Contains(help, "--"+opt.long) {
+					t.Errorf("--%s missing from help. got=\n%s", opt.long, help)
+				}
+			}
+		})
+	}
+
+	help := sprintHelp()
+	for _, cmd := range commands {
+		if !strings.Contains(help, cmd.summary) {
+			t.Errorf("%s missing from help. got=\n%s", cmd.name, help)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/site"
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
+)
+
+//go:embed test-website/themes/dark.css
+var darkTheme []byte
+
+const defaultTheme = "dark"
+
+type scaffoldFile struct {
+	name    string
+	content []byte
+}
+
+// initSite creates a new site in dir with everything site.Build expects:
+// an ssg.toml, a themes/ directory, a content/ directory with an example post
+// and a static/ directory.
+// existing files are only overwritten if opts.force is set
+func initSite(dir string, opts InitSiteOptions) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	config, err := defaultSiteConfig(filepath.Base(absDir), defaultAuthor())
+	if err != nil {
+		return fmt.Errorf("failed to create ssg.toml: %w", err)
+	}
+
+	post, err := examplePost(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create the example post: %w", err)
+	}
+
+	files := []scaffoldFile{
+		{"ssg.toml", config},
+		{filepath.Join("themes", defaultTheme+".css"), darkTheme},
+		{filepath.Join("content", "example.md"), post},
+	}
+
+	if !opts.force {
+		for _, file := range files {
+			_, err := os.Stat(filepath.Join(dir, file.name))
+			if err == nil {
+				return fmt.Errorf(
+					"%s already exists in %s. use --force to overwrite it",
+					file.name,
+					dir,
+				)
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to open %s: %w", file.name, err)
+			}
+		}
+	}
+
+	dirs := []string{"", "themes", "content", "static"}
+	for _, d := range dirs {
+		err := os.MkdirAll(filepath.Join(dir, d), 0755)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", d, err)
+		}
+	}
+
+	for _, file := range files {
+		err := os.WriteFile(filepath.Join(dir, file.name), file.content, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return nil
+}
+
+func defaultAuthor() string {
+	u, err := user.Current()
+	if err != nil || u.Username == "" {
+		return "anonymous"
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Username
+}
+
+func defaultSiteConfig(title, author string) ([]byte, error) {
+	return toml.Marshal(site.SiteConfig{
+		Title:  title,
+		Author: author,
+		Theme:  defaultTheme,
+		// written out so the options can be found in ssg.toml
+		Timezone:   "UTC",
+		DateFormat: site.DateLayout,
+		Feed:       site.FeedConfig{Enabled: true, Limit: 20},
+	})
+}
+
+func examplePost(date time.Time) ([]byte, error) {
+	post, err := frontMatter{Title: "Example post", Date: date}.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(post, `This is an example post. Posts are markdown files in content/ and each
+one is published as a page of the site.
+
+Images and other files go in static/.
+`...), nil
+}
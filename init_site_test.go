@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/site"
+)
+
+func TestInitSite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "blog")
+
+	err := initSite(dir, InitSiteOptions{})
+	if err != nil {
+		t.Fatalf("initSite failed: %v", err)
+	}
+
+	files := []string{
+		"ssg.toml",
+		"themes/dark.css",
+		"content/example.md",
+		"static",
+	}
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("%s was not created: %v", file, err)
+		}
+	}
+
+	s, err := site.Build(dir, site.BuildOptions{})
+	if err != nil {
+		t.Fatalf("failed to build initialized site: %v", err)
+	}
+
+	if s.Config.Title != "blog" {
+		t.Errorf("wrong site title. expected=%q. got=%q", "blog", s.Config.Title)
+	}
+
+	post := findHTMLNode(s.Nodes, "example post")
+	if post == nil {
+		t.Fatal("the example post wasn't built")
+	}
+	expected := "Posts are markdown files in content/ and each\none is published as a page of the site."
+	if !bytes.Contains(post.Content, []byte(expected)) {
+		t.Errorf("expected the example post to contain %q. got=\n%s", expected, post.Content)
+	}
+}
+
+// findHTMLNode returns the first html node in nodes that contains text
+func findHTMLNode(nodes []site.Node, text string) *site.Node {
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Type == site.DirectoryNode {
+			if found := findHTMLNode(node.Children, text); found != nil {
+				return found
+			}
+			continue
+		}
+		if node.Type == site.HTMLNode && bytes.Contains(node.Content, []byte(text)) {
+			return node
+		}
+	}
+	return nil
+}
+
+func TestInitSiteExisting(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "ssg.toml"), []byte("keep"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = initSite(dir, InitSiteOptions{})
+	if err == nil {
+		t.Fatal("expected initSite to refuse to overwrite ssg.toml")
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "ssg.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "keep" {
+		t.Errorf("ssg.toml was overwritten. got=%q", string(b))
+	}
+
+	err = initSite(dir, InitSiteOptions{force: true})
+	if err != nil {
+		t.Fatalf("initSite failed with force: %v", err)
+	}
+
+	if _, err := site.Build(dir, site.BuildOptions{}); err != nil {
+		t.Fatalf("failed to build initialized site: %v", err)
+	}
+}
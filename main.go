@@ -1,9 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"time"
+	// the time zone in ssg.toml has to work on systems without a zoneinfo database
+	_ "time/tzdata"
 
 	"github.com/Hassan-Ibrahim-1/go-ssg/server"
 	"github.com/Hassan-Ibrahim-1/go-ssg/site"
@@ -11,7 +16,7 @@ import (
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println(sprintUsage())
+		fmt.Println(sprintHelp())
 		return
 	}
 
@@ -22,6 +27,16 @@ func main() {
 	}
 
 	switch action.typ {
+	case ShowHelp:
+		if cmd := findCommand(action.helpCommand); cmd != nil {
+			fmt.Println(cmd.sprintHelp())
+		} else {
+			fmt.Println(sprintHelp())
+		}
+
+	case ShowVersion:
+		fmt.Println(sprintVersion())
+
 	case DevServer:
 		opts := action.devServerOpts
 		addr := fmt.Sprintf(":%d", opts.port)
@@ -47,12 +62,78 @@ func main() {
 		buildOpts := site.BuildOptions{BuildDrafts: opts.buildDrafts}
 		s, err := site.Build(action.siteDir, buildOpts)
 		if err != nil {
-			log.Fatalln(err)
+			fatalBuildError(err)
 		}
 
 		err = site.BuildSite(s, opts.buildDir)
 		if err != nil {
 			log.Fatalln(err)
 		}
+
+	case InitSite:
+		err := initSite(action.siteDir, action.initSiteOpts)
+		if err != nil {
+			log.Fatalln("failed to create site:", err)
+		}
+		fmt.Println("created a new site in", action.siteDir)
+
+	case NewPost:
+		path, err := newPost(action.siteDir, action.newPostOpts.path, time.Now())
+		if err != nil {
+			log.Fatalln("failed to create post:", err)
+		}
+		fmt.Println("created", path)
+
+	case CheckSite:
+		opts := action.checkSiteOpts
+
+		buildOpts := site.BuildOptions{BuildDrafts: opts.buildDrafts}
+		diagnostics, err := site.Check(action.siteDir, buildOpts)
+		if err != nil {
+			log.Fatalln("failed to check site:", err)
+		}
+
+		if opts.json {
+			err = printDiagnosticsJSON(diagnostics)
+			if err != nil {
+				log.Fatalln(err)
+			}
+		} else {
+			for _, d := range diagnostics {
+				fmt.Println(d)
+			}
+			fmt.Printf("found %d problem(s)\n", len(diagnostics))
+		}
+
+		if diagnostics.HasErrors() {
+			os.Exit(1)
+		}
+	}
+}
+
+// prints every diagnostic in err on its own line if there are any
+func fatalBuildError(err error) {
+	var diagnostics site.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		log.Fatalln(err)
+	}
+
+	for _, d := range diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+	}
+	fmt.Fprintf(
+		os.Stderr,
+		"build failed with %d error(s)\n",
+		diagnostics.ErrorCount(),
+	)
+	os.Exit(1)
+}
+
+func printDiagnosticsJSON(diagnostics site.Diagnostics) error {
+	if diagnostics == nil {
+		diagnostics = site.Diagnostics{}
 	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diagnostics)
 }
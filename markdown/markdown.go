@@ -8,7 +8,9 @@ import (
 )
 
 type HTMLDoc struct {
-	Metadata map[string]string
+	// Metadata is the front matter of the markdown file. values are one of
+	// string, int64, float64, bool, time.Time, []any or map[string]any
+	Metadata map[string]any
 	Content  []byte
 }
 
@@ -26,6 +28,11 @@ func ToHTML(md []byte) (HTMLDoc, error) {
 	}, nil
 }
 
+// Render converts markdown without front matter to sanitized html
+func Render(md []byte) []byte {
+	return convertMdSanitized(md)
+}
+
 func convertMdSanitized(md []byte) []byte {
 	unsanitized := markdown.ToHTML(md, nil, nil)
 	return bluemonday.UGCPolicy().SanitizeBytes(unsanitized)
@@ -2,50 +2,304 @@ package markdown
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
+	"gopkg.in/yaml.v3"
 )
 
+// MetadataError is returned when the metadata of a markdown file is invalid.
+// Line and Column are positions in the markdown file and start at 1.
+// Column is 0 if it is unknown
+type MetadataError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *MetadataError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// parseMetadata parses the front matter at the start of a markdown file.
+// it can be TOML between +++ lines, YAML between --- lines or a JSON object.
+// values have the same types as the ones returned by toml.Parse.
+//
 // map can be nil if there is no metadata
 // returns remaining contents of the markdown file not including the metadata
-func parseMetadata(md []byte) (map[string]string, []byte, error) {
+func parseMetadata(md []byte) (map[string]any, []byte, error) {
 	lines := bytes.Split(md, []byte{'\n'})
 
-	start, end := getMetadataSlice(lines)
+	first := 0
+	for first < len(lines) && isWhitespace(lines[first]) {
+		first++
+	}
+	if first == len(lines) {
+		return nil, md, nil
+	}
+
+	firstLine := bytes.TrimSpace(lines[first])
+	switch {
+	case bytes.Equal(firstLine, []byte("+++")):
+		return parseDelimitedMetadata(md, lines, "+++", parseTOMLMetadata)
+	case bytes.Equal(firstLine, []byte("---")):
+		return parseDelimitedMetadata(md, lines, "---", parseYAMLMetadata)
+	case bytes.HasPrefix(firstLine, []byte("{")):
+		offset := len(bytes.Join(lines[:first], []byte{'\n'}))
+		if first > 0 {
+			offset++
+		}
+		offset += bytes.IndexByte(lines[first], '{')
+		return parseJSONMetadata(md, offset)
+	}
+
+	return nil, md, nil
+}
+
+// parses the lines between start and end. start is the index of the
+// first line of the metadata in the file
+type metadataParser func(lines [][]byte, start int) (map[string]any, error)
+
+func parseDelimitedMetadata(
+	md []byte,
+	lines [][]byte,
+	delimiter string,
+	parse metadataParser,
+) (map[string]any, []byte, error) {
+	start, end := getMetadataSlice(lines, delimiter)
 	// no metadata
 	if start == -1 || end == -1 {
 		return nil, md, nil
 	}
 
-	metadata := lines[start:end]
+	metadata, err := parse(lines[start:end], start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// remove the delimiter
+	remaining := bytes.Join(lines[end+1:], []byte{'\n'})
+	return metadata, remaining, nil
+}
+
+func parseTOMLMetadata(lines [][]byte, start int) (map[string]any, error) {
+	metadata, err := toml.Parse(bytes.Join(lines, []byte{'\n'}))
+	if err == nil {
+		return metadata, nil
+	}
+
+	var parseErr *toml.ParseError
+	if !errors.As(err, &parseErr) {
+		return nil, &MetadataError{Line: start, Msg: err.Error()}
+	}
+
+	// older sites use unquoted values like title = My post. they are only
+	// used if the metadata isn't valid TOML and doesn't use any TOML syntax
+	if !usesTOMLSyntax(lines) {
+		legacy, legacyErr := parseLegacyMetadata(lines, start)
+		if legacyErr == nil {
+			return legacy, nil
+		}
+		// report the error of the format that made it further into the metadata
+		if legacyErr.Line > start+parseErr.Line {
+			return nil, legacyErr
+		}
+	}
+
+	return nil, &MetadataError{
+		Line:   start + parseErr.Line,
+		Column: parseErr.Column,
+		Msg:    parseErr.Msg,
+	}
+}
 
-	keyValues := make(map[string]string)
-	for _, line := range metadata {
+// usesTOMLSyntax reports whether any of lines has a table header, a quoted
+// key or value, an array or an inline table. none of them are part of the
+// legacy format
+func usesTOMLSyntax(lines [][]byte) bool {
+	for _, line := range lines {
 		trimmed := bytes.TrimSpace(line)
 		if len(trimmed) == 0 {
 			continue
 		}
+		if bytes.ContainsAny(trimmed[:1], "[\"'") {
+			return true
+		}
+
+		_, value, ok := bytes.Cut(trimmed, []byte{'='})
+		value = bytes.TrimSpace(value)
+		if ok && len(value) > 0 && bytes.ContainsAny(value[:1], "\"'[{") {
+			return true
+		}
+	}
+	return false
+}
 
-		kv := bytes.Split(trimmed, []byte{'='})
-		if len(kv) != 2 {
-			return nil, nil, fmt.Errorf(
-				"Not a valid key value pair %q, expected key = value",
-				line,
-			)
+func parseLegacyMetadata(lines [][]byte, start int) (map[string]any, *MetadataError) {
+	keyValues := make(map[string]any)
+	for i, line := range lines {
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
 		}
 
-		trimmedKey := string(bytes.TrimSpace(kv[0]))
-		trimmedValue := string(bytes.TrimSpace(kv[1]))
+		key, value, ok := bytes.Cut(trimmed, []byte{'='})
+		if !ok {
+			return nil, &MetadataError{
+				Line: start + i + 1,
+				Msg: fmt.Sprintf(
+					"Not a valid key value pair %q, expected key = value",
+					line,
+				),
+			}
+		}
+
+		trimmedKey := string(bytes.TrimSpace(key))
+		trimmedValue := string(bytes.TrimSpace(value))
 		keyValues[trimmedKey] = trimmedValue
 	}
+	return keyValues, nil
+}
 
-	// remove the '+++'
-	remaining := bytes.Join(lines[end+1:], []byte{'\n'})
-	return keyValues, remaining, nil
+var yamlLineRegexp = regexp.MustCompile(`line (\d+): (.*)`)
+
+func parseYAMLMetadata(lines [][]byte, start int) (map[string]any, error) {
+	var doc yaml.Node
+	var metadata map[string]any
+	err := yaml.Unmarshal(bytes.Join(lines, []byte{'\n'}), &doc)
+	if err == nil {
+		localDatesToStrings(&doc)
+		err = doc.Decode(&metadata)
+	}
+	if err != nil {
+		msg := strings.TrimPrefix(err.Error(), "yaml: ")
+		msg = strings.TrimPrefix(msg, "unmarshal errors:\n")
+		msg = strings.TrimSpace(msg)
+
+		if match := yamlLineRegexp.FindStringSubmatch(msg); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			return nil, &MetadataError{Line: start + line, Msg: match[2]}
+		}
+		return nil, &MetadataError{Line: start, Msg: msg}
+	}
+
+	if metadata == nil {
+		return map[string]any{}, nil
+	}
+	return normalizeMap(metadata), nil
+}
+
+// zone-less YAML dates are decoded in UTC. they're kept as strings so that
+// they're read in the site's time zone like the dates in JSON
+func localDatesToStrings(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!timestamp" && !hasZone(n.Value) {
+		n.Tag = "!!str"
+	}
+	for _, child := range n.Content {
+		localDatesToStrings(child)
+	}
+}
+
+var yamlZoneRegexp = regexp.MustCompile(`[0-9](Z|\s*[+-][0-9]{1,2}(:[0-9]{2})?)$`)
+
+// hasZone reports whether the YAML timestamp s ends with a time zone
+func hasZone(s string) bool {
+	// dates without a time can't have a zone
+	return len(s) > len("2006-01-02") && yamlZoneRegexp.MatchString(s)
+}
+
+// offset is the index of the { that starts the metadata
+func parseJSONMetadata(md []byte, offset int) (map[string]any, []byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(md[offset:]))
+	dec.UseNumber()
+
+	var metadata map[string]any
+	err := dec.Decode(&metadata)
+	if err != nil {
+		errOffset := len(md) - offset
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			errOffset = int(syntaxErr.Offset)
+		case errors.As(err, &typeErr):
+			errOffset = int(typeErr.Offset)
+		}
+		line := bytes.Count(md[:offset+errOffset], []byte{'\n'}) + 1
+		return nil, nil, &MetadataError{Line: line, Msg: err.Error()}
+	}
+
+	// remove the rest of the line the metadata ends on
+	remaining := md[offset+int(dec.InputOffset()):]
+	if i := bytes.IndexByte(remaining, '\n'); i != -1 && isWhitespace(remaining[:i]) {
+		remaining = remaining[i+1:]
+	} else if isWhitespace(remaining) {
+		remaining = nil
+	}
+
+	return normalizeMap(metadata), remaining, nil
+}
+
+// normalizeMap converts the values decoded from YAML and JSON to the types
+// used by toml.Parse. null values are removed so that they look like
+// missing keys
+func normalizeMap(m map[string]any) map[string]any {
+	normalized := make(map[string]any, len(m))
+	for k, v := range m {
+		if v == nil {
+			continue
+		}
+		normalized[k] = normalizeValue(v)
+	}
+	return normalized
+}
+
+func normalizeValue(v any) any {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case uint64:
+		if v <= uint64(1<<63-1) {
+			return int64(v)
+		}
+		return float64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		return normalizeMap(v)
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, value := range v {
+			m[fmt.Sprint(k)] = value
+		}
+		return normalizeMap(m)
+	case []any:
+		s := make([]any, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				s = append(s, normalizeValue(item))
+			}
+		}
+		return s
+	case time.Time, string, bool, int64, float64:
+		return v
+	}
+	return fmt.Sprint(v)
 }
 
 // returns -1, -1 if no metadata is found
-func getMetadataSlice(lines [][]byte) (start, end int) {
-	start = findByteSlice(lines, []byte("+++"))
+func getMetadataSlice(lines [][]byte, delimiter string) (start, end int) {
+	start = findByteSlice(lines, []byte(delimiter))
 	if start == -1 {
 		return -1, -1
 	}
@@ -54,12 +308,12 @@ func getMetadataSlice(lines [][]byte) (start, end int) {
 		return -1, -1
 	}
 
-	end = findByteSlice(lines[start+1:], []byte("+++"))
+	end = findByteSlice(lines[start+1:], []byte(delimiter))
 	if end == -1 {
 		return -1, -1
 	}
 
-	// +1 to remove the '+++' delimiters
+	// +1 to remove the delimiters
 	return start + 1, end + start + 1
 }
 
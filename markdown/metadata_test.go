@@ -3,14 +3,17 @@ package markdown
 import (
 	"bytes"
 	"fmt"
-	"maps"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
 )
 
 func TestParseMetadata(t *testing.T) {
 	tests := []struct {
 		input             string
-		expectedMetadata  map[string]string
+		expectedMetadata  map[string]any
 		expectedRemaining string
 		expectedErr       error
 	}{
@@ -23,7 +26,7 @@ description = A basic markdown file
 
 Hello, World
 `,
-			map[string]string{
+			map[string]any{
 				"author":      "Jane Doe",
 				"title":       "Test markdown",
 				"description": "A basic markdown file",
@@ -49,7 +52,7 @@ description = A basic markdown file
 
 Hello, World
 `,
-			map[string]string{
+			map[string]any{
 				"author":      "Jane Doe",
 				"title":       "Test markdown",
 				"description": "A basic markdown file",
@@ -65,7 +68,7 @@ description = A basic markdown file
 
 Hello, World
 `,
-			nil, "", fmt.Errorf("Not a valid key value pair \"title: Test markdown\", expected key = value"),
+			nil, "", fmt.Errorf("line 4: Not a valid key value pair \"title: Test markdown\", expected key = value"),
 		},
 		{`
 +++
@@ -128,22 +131,22 @@ Hello, World
 
 Hello, World
 `,
-			map[string]string{}, "\nHello, World\n", nil,
+			map[string]any{}, "\nHello, World\n", nil,
 		},
 		{`
 +++
 +++
 `,
-			map[string]string{}, "", nil,
+			map[string]any{}, "", nil,
 		},
-		{"Hello World", map[string]string{}, "Hello World", nil},
-		{"\nHello World", map[string]string{}, "\nHello World", nil},
+		{"Hello World", map[string]any{}, "Hello World", nil},
+		{"\nHello World", map[string]any{}, "\nHello World", nil},
 		{`
 +++
 +++
 Hello World
 `,
-			map[string]string{}, "Hello World\n", nil,
+			map[string]any{}, "Hello World\n", nil,
 		},
 		{`
 +++
@@ -152,7 +155,7 @@ true = !false
 +++
 Hello World
 `,
-			map[string]string{
+			map[string]any{
 				"key":  "value",
 				"true": "!false",
 			}, "Hello World\n", nil,
@@ -163,7 +166,136 @@ test
 +++
 Hello World
 `,
-			map[string]string{}, "\ntest\n+++\n+++\nHello World\n", nil,
+			map[string]any{}, "\ntest\n+++\n+++\nHello World\n", nil,
+		},
+		{`+++
+title = "a = b"
+date = 2024-01-02
+draft = false
+tags = ["go", "web"]
+weight = 2
+
+[cover]
+image = "cover.png"
++++
+Hello World
+`,
+			map[string]any{
+				"title":  "a = b",
+				"date":   time.Date(2024, 1, 2, 0, 0, 0, 0, toml.LocalDate),
+				"draft":  false,
+				"tags":   []any{"go", "web"},
+				"weight": int64(2),
+				"cover":  map[string]any{"image": "cover.png"},
+			}, "Hello World\n", nil,
+		},
+		{`+++
+title = a = b
++++
+Hello World
+`,
+			map[string]any{"title": "a = b"}, "Hello World\n", nil,
+		},
+		{`+++
+tags = ["a",
+draft: true
++++
+`,
+			nil, "", fmt.Errorf("line 3: invalid value draft. strings have to be quoted"),
+		},
+		// the legacy format isn't used once a line is written in TOML
+		{`+++
+title = "A post"
+date = 01-01-2000
++++
+Hello World
+`,
+			nil, "", fmt.Errorf("line 3: invalid number 01-01-2000"),
+		},
+		{`+++
+[params]
+author = Me
++++
+`,
+			nil, "", fmt.Errorf("line 3: invalid value Me. strings have to be quoted"),
+		},
+		{`---
+title: A post
+date: 2024-01-02T03:04:05Z
+draft: true
+weight: 3
+ratio: 0.5
+tags:
+  - go
+  - web
+cover:
+  image: cover.png
+  alt: ~
+---
+Hello World
+`,
+			map[string]any{
+				"title":  "A post",
+				"date":   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				"draft":  true,
+				"weight": int64(3),
+				"ratio":  0.5,
+				"tags":   []any{"go", "web"},
+				"cover":  map[string]any{"image": "cover.png"},
+			}, "Hello World\n", nil,
+		},
+		// dates without a time zone are left for the site to read
+		{`---
+date: 2024-01-02
+lastmod: 2024-01-02 03:04:05
+published: 2024-01-02T03:04:05+02:00
+---
+`,
+			map[string]any{
+				"date":      "2024-01-02",
+				"lastmod":   "2024-01-02 03:04:05",
+				"published": time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*60*60)),
+			}, "", nil,
+		},
+		{`---
+---
+Hello World
+`,
+			map[string]any{}, "Hello World\n", nil,
+		},
+		{`---
+title: A post
+  draft: true
+---
+`,
+			nil, "", fmt.Errorf("line 3: mapping values are not allowed in this context"),
+		},
+		{"---\nno closing delimiter\n", nil, "---\nno closing delimiter\n", nil},
+		{`
+{
+  "title": "A post",
+  "draft": true,
+  "weight": 3,
+  "ratio": 1.5,
+  "tags": ["go"],
+  "cover": {"image": "cover.png"},
+  "empty": null
+}
+Hello World
+`,
+			map[string]any{
+				"title":  "A post",
+				"draft":  true,
+				"weight": int64(3),
+				"ratio":  1.5,
+				"tags":   []any{"go"},
+				"cover":  map[string]any{"image": "cover.png"},
+			}, "Hello World\n", nil,
+		},
+		{`{"title": "A post",
+"draft": tru}
+`,
+			nil, "", fmt.Errorf("line 2: invalid character '}' in literal true (expecting 'e')"),
 		},
 	}
 
@@ -178,7 +310,7 @@ Hello World
 				)
 			}
 
-			if !maps.Equal(tt.expectedMetadata, metadata) {
+			if !metadataEqual(tt.expectedMetadata, metadata) {
 				t.Errorf(
 					"bad metadata. expected=%+v. got=%+v",
 					tt.expectedMetadata,
@@ -197,6 +329,14 @@ Hello World
 	}
 }
 
+// nil and empty metadata are the same
+func metadataEqual(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 func errEqual(err1, err2 error) bool {
 	if err1 == nil && err2 == nil {
 		return true
@@ -238,7 +378,7 @@ Hello, World
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
 			lines := bytes.Split([]byte(tt.input), []byte{'\n'})
-			start, end := getMetadataSlice(lines)
+			start, end := getMetadataSlice(lines, "+++")
 
 			if start != tt.start {
 				t.Errorf("wrong start. expected=%d. got=%d", tt.start, start)
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/site"
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
+)
+
+// newPost creates a draft post in the content/ directory of siteDir and
+// returns the path of the created file.
+// the last element of path is used as the post's title and is slugified
+// to get the file name. e.g. "notes/My Post" -> content/notes/my-post.md
+func newPost(siteDir, path string, date time.Time) (string, error) {
+	ssgToml, err := os.ReadFile(filepath.Join(siteDir, "ssg.toml"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read ssg.toml: %w", err)
+	}
+
+	var config site.SiteConfig
+	err = toml.Unmarshal(ssgToml, &config)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ssg.toml file: %w", err)
+	}
+
+	author := config.Author
+	if author == "" {
+		return "", fmt.Errorf("no author provided in ssg.toml")
+	}
+
+	path = strings.TrimSuffix(filepath.ToSlash(path), ".md")
+	elems := strings.Split(path, "/")
+
+	title := strings.TrimSpace(elems[len(elems)-1])
+	if site.Slugify(title) == "" {
+		return "", fmt.Errorf("%q is not a valid post title", title)
+	}
+
+	for i, elem := range elems {
+		elems[i] = site.Slugify(elem)
+	}
+
+	postPath := filepath.Join(siteDir, "content", filepath.Join(elems...)+".md")
+
+	_, err = os.Stat(postPath)
+	if err == nil {
+		return "", fmt.Errorf("%s already exists", postPath)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to open %s: %w", postPath, err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(postPath), 0755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(postPath), err)
+	}
+
+	post, err := frontMatter{
+		Title:  title,
+		Date:   date,
+		Author: author,
+		Draft:  true,
+	}.marshal()
+	if err != nil {
+		return "", fmt.Errorf("failed to write the front matter: %w", err)
+	}
+
+	err = os.WriteFile(postPath, post, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", postPath, err)
+	}
+
+	return postPath, nil
+}
+
+// frontMatter is the metadata at the start of the posts created by
+// the new and init commands
+type frontMatter struct {
+	Title  string    `toml:"title"`
+	Date   time.Time `toml:"date"`
+	Author string    `toml:"author,omitempty"`
+	Draft  bool      `toml:"draft,omitempty"`
+}
+
+// marshal returns the front matter as a TOML block.
+// only the day of the date is written
+func (fm frontMatter) marshal() ([]byte, error) {
+	fm.Date = time.Date(
+		fm.Date.Year(),
+		fm.Date.Month(),
+		fm.Date.Day(),
+		0, 0, 0, 0,
+		toml.LocalDate,
+	)
+
+	metadata, err := toml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("+++\n")
+	b.Write(metadata)
+	b.WriteString("+++\n\n")
+	return b.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/site"
+)
+
+func TestNewPost(t *testing.T) {
+	dir := t.TempDir()
+	err := initSite(dir, InitSiteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2025, time.August, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		path        string
+		expected    string
+		frontMatter string
+	}{
+		{
+			"My First Post",
+			"content/my-first-post.md",
+			"+++\ntitle = \"My First Post\"\ndate = 2025-08-15\nauthor = \"test author\"\ndraft = true\n+++\n\n",
+		},
+		{
+			"Dev Notes/Week 1.md",
+			"content/dev-notes/week-1.md",
+			"+++\ntitle = \"Week 1\"\ndate = 2025-08-15\nauthor = \"test author\"\ndraft = true\n+++\n\n",
+		},
+	}
+
+	config, err := defaultSiteConfig("test blog", "test author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "ssg.toml"), config, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := newPost(dir, tt.path, date)
+			if err != nil {
+				t.Fatalf("newPost failed: %v", err)
+			}
+
+			if path != filepath.Join(dir, tt.expected) {
+				t.Fatalf(
+					"wrong path. expected=%q. got=%q",
+					filepath.Join(dir, tt.expected),
+					path,
+				)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.frontMatter {
+				t.Errorf(
+					"wrong front matter. expected=%q. got=%q",
+					tt.frontMatter,
+					string(b),
+				)
+			}
+
+			_, err = newPost(dir, tt.path, date)
+			if err == nil {
+				t.Errorf("expected newPost to refuse to overwrite %s", path)
+			}
+		})
+	}
+
+	if _, err := site.Build(dir, site.BuildOptions{BuildDrafts: true}); err != nil {
+		t.Fatalf("failed to build site with new posts: %v", err)
+	}
+}
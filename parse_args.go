@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
 type ActionType int
@@ -11,6 +13,11 @@ type ActionType int
 const (
 	BuildSite ActionType = iota
 	DevServer
+	InitSite
+	NewPost
+	CheckSite
+	ShowHelp
+	ShowVersion
 )
 
 type BuildSiteOptions struct {
@@ -23,12 +30,34 @@ type DevServerOptions struct {
 	buildDrafts bool
 }
 
+type InitSiteOptions struct {
+	// overwrite the files of an existing site
+	force bool
+}
+
+type NewPostOptions struct {
+	// path of the post relative to content/. the last element is the title
+	path string
+}
+
+type CheckSiteOptions struct {
+	buildDrafts bool
+	// print the problems as json
+	json bool
+}
+
 type Action struct {
 	typ     ActionType
 	siteDir string
 
 	buildSiteOpts BuildSiteOptions
 	devServerOpts DevServerOptions
+	initSiteOpts  InitSiteOptions
+	newPostOpts   NewPostOptions
+	checkSiteOpts CheckSiteOptions
+
+	// command to show the help of. empty for the general help
+	helpCommand string
 }
 
 const (
@@ -36,56 +65,201 @@ const (
 	DefaultBuildDirectory = "ssg-build"
 )
 
-// TODO: help menu
+// set with -ldflags "-X main.version=..."
+var version = ""
+
+func sprintVersion() string {
+	if version != "" {
+		return "ssg " + version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if ok && info.Main.Version != "" {
+		return "ssg " + info.Main.Version
+	}
+	return "ssg (devel)"
+}
+
+var draftOption = option{
+	long:  "draft",
+	short: "D",
+	kind:  boolOption,
+	help:  "include drafts",
+}
+
+var commands = []command{
+	{
+		name:    "build",
+		summary: "build the site into a directory that can be deployed",
+		args:    []string{"directory"},
+		options: []option{
+			draftOption,
+			{
+				long:      "build-dir",
+				short:     "o",
+				kind:      valueOption,
+				valueName: "directory",
+				example:   DefaultBuildDirectory,
+				help: fmt.Sprintf(
+					"directory to write the site to (default %s)",
+					DefaultBuildDirectory,
+				),
+			},
+		},
+		action: buildSiteAction,
+	},
+	{
+		name:    "dev",
+		summary: "start a development server that rebuilds the site when files change",
+		args:    []string{"directory"},
+		options: []option{
+			draftOption,
+			{
+				long:      "port",
+				short:     "p",
+				kind:      valueOption,
+				valueName: "number",
+				example:   strconv.Itoa(DefaultServerPort),
+				help: fmt.Sprintf(
+					"port to listen on (default %d)",
+					DefaultServerPort,
+				),
+			},
+		},
+		action: devServerAction,
+	},
+	{
+		name:    "init",
+		summary: "create a new site with an ssg.toml, a theme and an example post",
+		args:    []string{"directory"},
+		options: []option{
+			{
+				long:  "force",
+				short: "f",
+				kind:  boolOption,
+				help:  "overwrite the files of an existing site",
+			},
+		},
+		action: initSiteAction,
+	},
+	{
+		name:    "new",
+		summary: "create a draft post in content/. the last element of path is the title",
+		args:    []string{"directory", "path"},
+		action:  newPostAction,
+	},
+	{
+		name:    "check",
+		summary: "report every problem in the site without building it",
+		args:    []string{"directory"},
+		options: []option{
+			draftOption,
+			{
+				long: "json",
+				kind: boolOption,
+				help: "print the problems as json",
+			},
+		},
+		action: checkSiteAction,
+	},
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func sprintCommandNames() string {
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.name
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
 
 func sprintUsage() string {
-	return "usage: ssg [dev / build] [directory]"
+	return "usage: ssg [command] [directory] [options]"
 }
 
-// ssg dev [directory] --port= -D --drafts
-// ssg build [directory] --build-dir= -D --drafts
-func parseArgs(args []string) (Action, error) {
-	if len(args) < 2 {
-		return Action{}, fmt.Errorf("%s", sprintUsage())
+func sprintHelp() string {
+	var help strings.Builder
+	help.WriteString(sprintUsage() + "\n\ncommands:\n")
+
+	w := tabwriter.NewWriter(&help, 0, 0, 4, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(w, "  help\tshow the help of a command\n")
+	w.Flush()
 
-	action := Action{}
-	action.siteDir = args[1]
+	help.WriteString("\noptions:\n")
+	w = tabwriter.NewWriter(&help, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "  -h, --help\tshow this help\n")
+	fmt.Fprintf(w, "  -v, --version\tprint the version\n")
+	w.Flush()
 
-	var err error
+	help.WriteString("\nrun 'ssg help [command]' for the options of a command")
+	return help.String()
+}
+
+// ssg dev [directory] -p --port -D --draft
+// ssg build [directory] -o --build-dir -D --draft
+// ssg init [directory] -f --force
+// ssg new [directory] [path]
+// ssg check [directory] -D --draft --json
+// ssg help [command]
+func parseArgs(args []string) (Action, error) {
+	if len(args) == 0 {
+		return Action{}, fmt.Errorf("%s", sprintUsage())
+	}
 
 	switch args[0] {
-	case "build":
-		action.typ = BuildSite
-
-		if len(args) > 2 {
-			action.buildSiteOpts, err = parseBuildSiteOptions(args[2:])
-			if err != nil {
-				return Action{}, fmt.Errorf("failed to parse options: %w", err)
-			}
-		} else {
-			action.buildSiteOpts = defaultBuildSiteOptions()
+	case "help", "-h", "--help":
+		if len(args) == 1 {
+			return Action{typ: ShowHelp}, nil
 		}
-
-	case "dev":
-		action.typ = DevServer
-
-		if len(args) > 2 {
-			action.devServerOpts, err = parseDevServerOptions(args[2:])
-			if err != nil {
-				return Action{}, fmt.Errorf("failed to parse options: %w", err)
-			}
-		} else {
-			action.devServerOpts = defaultDevServerOpts()
+		if len(args) > 2 || findCommand(args[1]) == nil {
+			return Action{}, fmt.Errorf(
+				"invalid command %s. expected %s",
+				strings.Join(args[1:], " "),
+				sprintCommandNames(),
+			)
 		}
+		return Action{typ: ShowHelp, helpCommand: args[1]}, nil
+
+	case "version", "-v", "--version":
+		return Action{typ: ShowVersion}, nil
+	}
 
-	default:
+	cmd := findCommand(args[0])
+	if cmd == nil {
 		return Action{}, fmt.Errorf(
-			"invalid command %s. expected build or dev",
+			"invalid command %s. expected %s",
 			args[0],
+			sprintCommandNames(),
 		)
 	}
 
+	parsed, err := cmd.parse(args[1:])
+	if err != nil {
+		return Action{}, fmt.Errorf("failed to parse options: %w", err)
+	}
+
+	if parsed.bool(helpOption.long) {
+		return Action{typ: ShowHelp, helpCommand: cmd.name}, nil
+	}
+
+	if len(parsed.positional) != len(cmd.args) {
+		return Action{}, fmt.Errorf("%s", cmd.sprintUsage())
+	}
+
+	action, err := cmd.action(parsed)
+	if err != nil {
+		return Action{}, fmt.Errorf("failed to parse options: %w", err)
+	}
 	return action, nil
 }
 
@@ -97,121 +271,64 @@ func defaultDevServerOpts() DevServerOptions {
 	return DevServerOptions{DefaultServerPort, false}
 }
 
-func parseBuildSiteOptions(opts []string) (BuildSiteOptions, error) {
+func buildSiteAction(parsed parsedArgs) (Action, error) {
 	bso := defaultBuildSiteOptions()
 
-	foundDraftOpt := false
-	foundBuildDirOpt := false
-
-	for _, opt := range opts {
-		switch opt {
-		case "-D", "--draft", "--draft=true":
-			if foundDraftOpt {
-				return BuildSiteOptions{}, fmt.Errorf(
-					"multiple options given for --draft",
-				)
-			}
-			bso.buildDrafts = true
-			foundDraftOpt = true
-		case "--draft=false":
-			if foundDraftOpt {
-				return BuildSiteOptions{}, fmt.Errorf(
-					"multiple options given for --draft",
-				)
-			}
-			foundDraftOpt = true
-			bso.buildDrafts = false
-		default:
-			if strings.HasPrefix(opt, "--build-dir") {
-				if foundBuildDirOpt {
-					return BuildSiteOptions{}, fmt.Errorf(
-						"multiple options given for --build-dir",
-					)
-				}
-
-				if !strings.Contains(opt, "=") {
-					return BuildSiteOptions{}, fmt.Errorf(
-						"expected a directory for --build-dir. example --build-dir=ssg-build",
-					)
-				}
-
-				foundBuildDirOpt = true
-				bso.buildDir = strings.Split(opt, "=")[1]
-				continue
-			}
-			return BuildSiteOptions{}, fmt.Errorf(
-				"unrecognized option: %s",
-				opt,
-			)
-		}
+	bso.buildDrafts = parsed.bool("draft")
+	if parsed.has("build-dir") {
+		bso.buildDir = parsed.values["build-dir"]
 	}
 
-	return bso, nil
+	return Action{
+		typ:           BuildSite,
+		siteDir:       parsed.positional[0],
+		buildSiteOpts: bso,
+	}, nil
 }
 
-func parseDevServerOptions(opts []string) (DevServerOptions, error) {
+func devServerAction(parsed parsedArgs) (Action, error) {
 	dso := defaultDevServerOpts()
 
-	foundDraftOpt := false
-	foundPortOpt := false
-
-	for _, opt := range opts {
-		switch opt {
-		case "-D", "--draft", "--draft=true":
-			if foundDraftOpt {
-				return DevServerOptions{}, fmt.Errorf(
-					"multiple options given for --draft",
-				)
-			}
-			dso.buildDrafts = true
-			foundDraftOpt = true
-
-		case "--draft=false":
-			if foundDraftOpt {
-				return DevServerOptions{}, fmt.Errorf(
-					"multiple options given for --draft",
-				)
-			}
-			foundDraftOpt = true
-			dso.buildDrafts = false
-		default:
-			if strings.HasPrefix(opt, "--port") {
-				if foundPortOpt {
-					return DevServerOptions{}, fmt.Errorf(
-						"multiple options given for --port",
-					)
-				}
-				foundPortOpt = true
-
-				if !strings.Contains(opt, "=") {
-					return DevServerOptions{}, fmt.Errorf(
-						"expected a number to be given for port. ex: --port=4200",
-					)
-				}
-
-				num := strings.Split(opt, "=")[1]
-				port, err := strconv.ParseInt(
-					num,
-					10,
-					16,
-				)
-				if err != nil {
-					return DevServerOptions{}, fmt.Errorf(
-						"failed to parse number %s",
-						num,
-					)
-				}
-				dso.port = int(port)
-				continue
-			}
-
-			return DevServerOptions{}, fmt.Errorf(
-				"unrecognized option: %s",
-				opt,
-			)
+	dso.buildDrafts = parsed.bool("draft")
+	if parsed.has("port") {
+		num := parsed.values["port"]
+		port, err := strconv.ParseUint(num, 10, 16)
+		if err != nil {
+			return Action{}, fmt.Errorf("failed to parse number %s", num)
 		}
+		dso.port = int(port)
 	}
 
-	return dso, nil
+	return Action{
+		typ:           DevServer,
+		siteDir:       parsed.positional[0],
+		devServerOpts: dso,
+	}, nil
+}
+
+func initSiteAction(parsed parsedArgs) (Action, error) {
+	return Action{
+		typ:          InitSite,
+		siteDir:      parsed.positional[0],
+		initSiteOpts: InitSiteOptions{force: parsed.bool("force")},
+	}, nil
+}
+
+func newPostAction(parsed parsedArgs) (Action, error) {
+	return Action{
+		typ:         NewPost,
+		siteDir:     parsed.positional[0],
+		newPostOpts: NewPostOptions{path: parsed.positional[1]},
+	}, nil
+}
 
+func checkSiteAction(parsed parsedArgs) (Action, error) {
+	return Action{
+		typ:     CheckSite,
+		siteDir: parsed.positional[0],
+		checkSiteOpts: CheckSiteOptions{
+			buildDrafts: parsed.bool("draft"),
+			json:        parsed.bool("json"),
+		},
+	}, nil
 }
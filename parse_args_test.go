@@ -24,7 +24,7 @@ func TestParseArgs(t *testing.T) {
 		{
 			"build",
 			Action{},
-			fmt.Errorf("usage: ssg [dev / build] [directory]"),
+			fmt.Errorf("usage: ssg build [directory] [options]"),
 		},
 		{
 			"build site --draft",
@@ -171,7 +171,7 @@ func TestParseArgs(t *testing.T) {
 			"build site --bad=true --draft",
 			Action{},
 			fmt.Errorf(
-				"failed to parse options: unrecognized option: --bad=true",
+				"failed to parse options: unrecognized option: --bad=true. valid options are -D/--draft, -o/--build-dir, -h/--help",
 			),
 		},
 		{
@@ -210,10 +210,94 @@ func TestParseArgs(t *testing.T) {
 			),
 		},
 
+		{
+			"build site --build-dir out",
+			Action{
+				typ:           BuildSite,
+				siteDir:       "site",
+				buildSiteOpts: BuildSiteOptions{"out", false},
+			},
+			nil,
+		},
+		{
+			"build site -o out -D",
+			Action{
+				typ:           BuildSite,
+				siteDir:       "site",
+				buildSiteOpts: BuildSiteOptions{"out", true},
+			},
+			nil,
+		},
+		{
+			"build -D site",
+			Action{
+				typ:           BuildSite,
+				siteDir:       "site",
+				buildSiteOpts: BuildSiteOptions{DefaultBuildDirectory, true},
+			},
+			nil,
+		},
+		{
+			"build site --build-dir=a=b",
+			Action{
+				typ:           BuildSite,
+				siteDir:       "site",
+				buildSiteOpts: BuildSiteOptions{"a=b", false},
+			},
+			nil,
+		},
+		{
+			"build site -o=out",
+			Action{
+				typ:           BuildSite,
+				siteDir:       "site",
+				buildSiteOpts: BuildSiteOptions{"out", false},
+			},
+			nil,
+		},
+		{
+			"build site --build-dir -D",
+			Action{},
+			fmt.Errorf(
+				"failed to parse options: expected a directory for --build-dir. example --build-dir=ssg-build",
+			),
+		},
+		{
+			"build site --draft=maybe",
+			Action{},
+			fmt.Errorf(
+				`failed to parse options: invalid value "maybe" for --draft. expected true or false`,
+			),
+		},
+		{
+			"build site other",
+			Action{},
+			fmt.Errorf("usage: ssg build [directory] [options]"),
+		},
+		{
+			"build site --help",
+			Action{typ: ShowHelp, helpCommand: "build"},
+			nil,
+		},
+		{
+			"build -h",
+			Action{typ: ShowHelp, helpCommand: "build"},
+			nil,
+		},
+		{
+			"build -- -site",
+			Action{
+				typ:           BuildSite,
+				siteDir:       "-site",
+				buildSiteOpts: defaultBuildSiteOptions(),
+			},
+			nil,
+		},
+
 		{
 			"dne oops",
 			Action{},
-			fmt.Errorf("invalid command dne. expected build or dev"),
+			fmt.Errorf("invalid command dne. expected build, dev, init, new or check"),
 		},
 
 		{
@@ -228,7 +312,7 @@ func TestParseArgs(t *testing.T) {
 		{
 			"dev",
 			Action{},
-			fmt.Errorf("usage: ssg [dev / build] [directory]"),
+			fmt.Errorf("usage: ssg dev [directory] [options]"),
 		},
 		{
 			"dev site --port=80",
@@ -259,7 +343,7 @@ func TestParseArgs(t *testing.T) {
 			"dev site --bad=true",
 			Action{},
 			fmt.Errorf(
-				"failed to parse options: unrecognized option: --bad=true",
+				"failed to parse options: unrecognized option: --bad=true. valid options are -D/--draft, -p/--port, -h/--help",
 			),
 		},
 		{
@@ -342,13 +426,172 @@ func TestParseArgs(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"dev site --port 8080",
+			Action{
+				typ:           DevServer,
+				siteDir:       "site",
+				devServerOpts: DevServerOptions{8080, false},
+			},
+			nil,
+		},
+		{
+			"dev site -p 8080 -D",
+			Action{
+				typ:           DevServer,
+				siteDir:       "site",
+				devServerOpts: DevServerOptions{8080, true},
+			},
+			nil,
+		},
+		{
+			"dev site --port=-1",
+			Action{},
+			fmt.Errorf("failed to parse options: failed to parse number -1"),
+		},
 		{
 			"dev site --port",
 			Action{},
 			fmt.Errorf(
-				"failed to parse options: expected a number to be given for port. ex: --port=4200",
+				"failed to parse options: expected a number for --port. example --port=4200",
 			),
 		},
+
+		{
+			"init site",
+			Action{
+				typ:     InitSite,
+				siteDir: "site",
+			},
+			nil,
+		},
+		{
+			"init",
+			Action{},
+			fmt.Errorf("usage: ssg init [directory] [options]"),
+		},
+		{
+			"init site --force",
+			Action{
+				typ:          InitSite,
+				siteDir:      "site",
+				initSiteOpts: InitSiteOptions{force: true},
+			},
+			nil,
+		},
+		{
+			"init site -f",
+			Action{
+				typ:          InitSite,
+				siteDir:      "site",
+				initSiteOpts: InitSiteOptions{force: true},
+			},
+			nil,
+		},
+		{
+			"init site -f --force",
+			Action{},
+			fmt.Errorf(
+				"failed to parse options: multiple options given for --force",
+			),
+		},
+		{
+			"init site --draft",
+			Action{},
+			fmt.Errorf(
+				"failed to parse options: unrecognized option: --draft. valid options are -f/--force, -h/--help",
+			),
+		},
+
+		{
+			"new site first-post",
+			Action{
+				typ:         NewPost,
+				siteDir:     "site",
+				newPostOpts: NewPostOptions{path: "first-post"},
+			},
+			nil,
+		},
+		{
+			"new site notes/first-post",
+			Action{
+				typ:         NewPost,
+				siteDir:     "site",
+				newPostOpts: NewPostOptions{path: "notes/first-post"},
+			},
+			nil,
+		},
+		{
+			"new site --help",
+			Action{typ: ShowHelp, helpCommand: "new"},
+			nil,
+		},
+		{
+			"new site",
+			Action{},
+			fmt.Errorf("usage: ssg new [directory] [path]"),
+		},
+		{
+			"new site first-post second-post",
+			Action{},
+			fmt.Errorf("usage: ssg new [directory] [path]"),
+		},
+
+		{
+			"check site",
+			Action{
+				typ:     CheckSite,
+				siteDir: "site",
+			},
+			nil,
+		},
+		{
+			"check site -D --json",
+			Action{
+				typ:           CheckSite,
+				siteDir:       "site",
+				checkSiteOpts: CheckSiteOptions{buildDrafts: true, json: true},
+			},
+			nil,
+		},
+		{
+			"check site --json=yes",
+			Action{},
+			fmt.Errorf(
+				`failed to parse options: invalid value "yes" for --json. expected true or false`,
+			),
+		},
+
+		{
+			"help",
+			Action{typ: ShowHelp},
+			nil,
+		},
+		{
+			"--help",
+			Action{typ: ShowHelp},
+			nil,
+		},
+		{
+			"help dev",
+			Action{typ: ShowHelp, helpCommand: "dev"},
+			nil,
+		},
+		{
+			"help dne",
+			Action{},
+			fmt.Errorf("invalid command dne. expected build, dev, init, new or check"),
+		},
+		{
+			"--version",
+			Action{typ: ShowVersion},
+			nil,
+		},
+		{
+			"-v",
+			Action{typ: ShowVersion},
+			nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -32,10 +32,28 @@ type Server struct {
 
 func (s *Server) handler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if p := s.trimBasePath(r.URL.Path); p != r.URL.Path {
+			r = r.Clone(r.Context())
+			r.URL.Path = p
+			r.URL.RawPath = ""
+		}
 		s.mux.ServeHTTP(w, r)
 	}
 }
 
+// trimBasePath removes the path of base_url from the url path p. links
+// start with it but the site is served from the root
+func (s *Server) trimBasePath(p string) string {
+	base := s.site.Config.BasePath()
+	if base == "" {
+		return p
+	}
+	if rest, ok := strings.CutPrefix(p, base); ok && (rest == "" || rest[0] == '/') {
+		return "/" + strings.TrimPrefix(rest, "/")
+	}
+	return p
+}
+
 func (s *Server) ListenAndServe() error {
 	return s.server.ListenAndServe()
 }
@@ -210,7 +228,7 @@ func (s *Server) eventHandler() http.HandlerFunc {
 			http.Error(w, "Bad url", http.StatusBadRequest)
 			return
 		}
-		path := trimSlash(url.Path)
+		path := trimSlash(s.trimBasePath(url.Path))
 
 	refreshLoop:
 		for {
@@ -223,7 +241,7 @@ func (s *Server) eventHandler() http.HandlerFunc {
 					break refreshLoop
 				}
 
-				node := matchNode(s.site.Nodes, path)
+				node := pageNode(s.site.Nodes, path)
 				if node == nil {
 					log.Println("could not find node", path)
 					return
@@ -240,6 +258,19 @@ func (s *Server) eventHandler() http.HandlerFunc {
 	}
 }
 
+// pageNode returns the node of the page at the url path. pages with pretty
+// urls are directories so their index.html is used
+func pageNode(nodes []site.Node, path string) *site.Node {
+	if path == "" {
+		path = "index.html"
+	}
+	node := matchNode(nodes, path)
+	if node != nil && node.Type == site.DirectoryNode {
+		return indexNode(*node)
+	}
+	return node
+}
+
 func matchNode(nodes []site.Node, name string) *site.Node {
 	for _, node := range nodes {
 		if node.Name == name {
@@ -298,7 +329,7 @@ func newNodeHandler(nodes []site.Node) (*http.ServeMux, error) {
 
 		// this check is done here because all the reserved names can only
 		// conflict with directories at the root level.
-		if isReserved(node.Name) {
+		if site.IsReserved(node.Name) {
 			return nil, fmt.Errorf(
 				"/%s is reserved for the server, choose another directory name",
 				node.Name,
@@ -306,13 +337,7 @@ func newNodeHandler(nodes []site.Node) (*http.ServeMux, error) {
 		}
 
 		if isIndex(node.Name) {
-			mux.HandleFunc(
-				"/",
-				func(w http.ResponseWriter, r *http.Request) {
-					w.Header().Set("Content-Type", "text/html")
-					w.Write(node.Content)
-				},
-			)
+			mux.Handle("/", nodeHandler(node))
 			break
 		}
 	}
@@ -326,19 +351,16 @@ func addNodesToMux(nodes []site.Node, mux *http.ServeMux) {
 	}
 
 	for _, node := range nodes {
+		if !site.IsPublished(node) {
+			continue
+		}
 		mux.Handle("/"+node.Name, nodeHandler(node))
 
 		if len(node.Children) != 0 {
 			addNodesToMux(node.Children, mux)
 
 			if index := indexNode(node); index != nil {
-				mux.HandleFunc(
-					"/"+node.Name+"/",
-					func(w http.ResponseWriter, r *http.Request) {
-						w.Header().Set("Content-Type", "text/html")
-						w.Write(index.Content)
-					},
-				)
+				mux.Handle("/"+node.Name+"/", nodeHandler(*index))
 			}
 		}
 	}
@@ -347,20 +369,29 @@ func addNodesToMux(nodes []site.Node, mux *http.ServeMux) {
 func nodeHandler(node site.Node) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// fmt.Println("/" + node.Name + " is handling the request")
+		// aliases of pages that moved
+		if node.Redirect != "" {
+			http.Redirect(w, r, node.Redirect, http.StatusMovedPermanently)
+			return
+		}
+
 		switch node.Type {
 		case site.HTMLNode:
 			w.Header().Set("Content-Type", "text/html")
 			w.Write(node.Content)
 
 		case site.FileNode:
+			if node.Path != "" {
+				http.ServeFile(w, r, node.Path)
+				return
+			}
 			ext := filepath.Ext(node.Name)
 			w.Header().Set("Content-Type", mime.TypeByExtension(ext))
 			w.Write(node.Content)
 
 		case site.DirectoryNode:
 			if index := indexNode(node); index != nil {
-				w.Header().Set("Content-Type", "text/html")
-				w.Write(index.Content)
+				nodeHandler(*index).ServeHTTP(w, r)
 				return
 			}
 			// does this make sense?
@@ -384,11 +415,3 @@ func indexNode(node site.Node) *site.Node {
 func isIndex(nodeName string) bool {
 	return strings.HasSuffix(nodeName, "index.html")
 }
-
-func isReserved(name string) bool {
-	switch name {
-	case "fsevents":
-		return true
-	}
-	return false
-}
@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Hassan-Ibrahim-1/go-ssg/site"
+	"github.com/gorilla/websocket"
 )
 
 func defaultTestSite() []site.Node {
@@ -58,6 +63,34 @@ func defaultTestSite() []site.Node {
 		}}
 }
 
+// pages are built into <slug>/index.html
+func prettyTestSite() []site.Node {
+	return []site.Node{
+		{
+			Name:    "index.html",
+			Type:    site.HTMLNode,
+			Content: []byte("index"),
+		},
+		{
+			Name: "content",
+			Type: site.DirectoryNode,
+			Children: []site.Node{
+				{
+					Name: "content/post",
+					Type: site.DirectoryNode,
+					Children: []site.Node{
+						{
+							Name:    "content/post/index.html",
+							Type:    site.HTMLNode,
+							Content: []byte("post"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func TestNodeHandler(t *testing.T) {
 	tests := []struct {
 		nodes       []site.Node
@@ -109,6 +142,10 @@ func TestNodeHandler(t *testing.T) {
 				},
 			}, "/content/foo.html", "index",
 		},
+		{prettyTestSite(), "/content/post/", "post"},
+		{prettyTestSite(), "/content/post", "post"},
+		{prettyTestSite(), "/content/post/index.html", "post"},
+		{prettyTestSite(), "/content/", "index"},
 	}
 
 	for i, tt := range tests {
@@ -139,3 +176,220 @@ func TestNodeHandler(t *testing.T) {
 		)
 	}
 }
+
+func TestFileNodeFromDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.png")
+	err := os.WriteFile(path, []byte("image from disk"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := []site.Node{
+		{
+			Name:    "index.html",
+			Type:    site.HTMLNode,
+			Content: []byte("index"),
+		},
+		{
+			Name:    "ssg.toml",
+			Type:    site.FileNode,
+			Content: []byte("title = \"blog\""),
+		},
+		{
+			Name: "static",
+			Type: site.DirectoryNode,
+			Children: []site.Node{
+				{
+					Name: "static/image.png",
+					Type: site.FileNode,
+					Path: path,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		requestPath string
+		expected    string
+	}{
+		{"/static/image.png", "image from disk"},
+		// ssg.toml isn't part of the built site
+		{"/ssg.toml", "index"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requestPath, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.requestPath, nil)
+			rc := httptest.NewRecorder()
+
+			n, err := newNodeHandler(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n.ServeHTTP(rc, req)
+
+			if body := rc.Body.String(); body != tt.expected {
+				t.Errorf(
+					"unexpected body. expected=%s\n got=%s",
+					tt.expected,
+					body,
+				)
+			}
+		})
+	}
+}
+
+func TestAliasRedirects(t *testing.T) {
+	nodes := prettyTestSite()
+	nodes = append(nodes,
+		site.Node{
+			Name: "old",
+			Type: site.DirectoryNode,
+			Children: []site.Node{
+				{
+					Name:     "old/index.html",
+					Type:     site.HTMLNode,
+					Content:  []byte("moved"),
+					Redirect: "/content/post/",
+				},
+			},
+		},
+		site.Node{
+			Name:     "post.html",
+			Type:     site.HTMLNode,
+			Content:  []byte("moved"),
+			Redirect: "/content/post/",
+		},
+	)
+
+	tests := []struct {
+		requestPath string
+		code        int
+		location    string
+	}{
+		{"/old/", http.StatusMovedPermanently, "/content/post/"},
+		{"/old", http.StatusMovedPermanently, "/content/post/"},
+		{"/old/index.html", http.StatusMovedPermanently, "/content/post/"},
+		{"/post.html", http.StatusMovedPermanently, "/content/post/"},
+		{"/content/post/", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requestPath, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.requestPath, nil)
+			rc := httptest.NewRecorder()
+
+			n, err := newNodeHandler(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n.ServeHTTP(rc, req)
+
+			if rc.Code != tt.code {
+				t.Errorf("wrong status. expected=%d got=%d", tt.code, rc.Code)
+			}
+			if location := rc.Header().Get("Location"); location != tt.location {
+				t.Errorf("wrong location. expected=%q got=%q", tt.location, location)
+			}
+		})
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	mux, err := newNodeHandler(prettyTestSite())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		site: site.Site{Config: site.SiteConfig{BaseURL: "https://example.com/blog/"}},
+		mux:  mux,
+	}
+
+	tests := []struct {
+		requestPath string
+		expected    string
+	}{
+		{"/blog/content/post/", "post"},
+		{"/blog/", "index"},
+		{"/blog", "index"},
+		{"/content/post/", "post"},
+		{"/blogs/content/post/", "index"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requestPath, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.requestPath, nil)
+			rc := httptest.NewRecorder()
+			s.handler().ServeHTTP(rc, req)
+
+			if body := rc.Body.String(); body != tt.expected {
+				t.Errorf(
+					"unexpected body. expected=%s\n got=%s",
+					tt.expected,
+					body,
+				)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		url      string
+		expected string
+	}{
+		{"", "http://localhost:8080/content/post/", "post"},
+		{"", "http://localhost:8080/content/post", "post"},
+		{"", "http://localhost:8080/", "index"},
+		{"https://example.com/blog/", "http://localhost:8080/blog/content/post/", "post"},
+		{"https://example.com/blog/", "http://localhost:8080/blog/", "index"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			s := &Server{
+				site: site.Site{
+					Config: site.SiteConfig{BaseURL: tt.baseURL},
+					Nodes:  prettyTestSite(),
+				},
+				clients: make(map[int]chan struct{}),
+			}
+			ts := httptest.NewServer(s.eventHandler())
+			defer ts.Close()
+
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(tt.url)); err != nil {
+				t.Fatal(err)
+			}
+			waitForClient(s)
+			s.pingClients()
+
+			_, content, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("wrong page. expected=%s got=%s", tt.expected, content)
+			}
+		})
+	}
+}
+
+// waitForClient waits until a client is connected to s
+func waitForClient(s *Server) {
+	for {
+		s.clientsMu.Lock()
+		n := len(s.clients)
+		s.clientsMu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
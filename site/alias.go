@@ -0,0 +1,146 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	redirectsName = "_redirects"
+	nginxMapName  = "redirects.map"
+)
+
+// RedirectsConfig is the [redirects] table in ssg.toml. alias pages are
+// always generated, these files let hosts redirect on the server instead
+type RedirectsConfig struct {
+	// Netlify writes the aliases to _redirects. it's read by hosts like
+	// Netlify and Cloudflare Pages
+	Netlify bool `toml:"netlify"`
+	// Nginx writes the aliases to redirects.map. it can be included in
+	// an nginx map block
+	Nginx bool `toml:"nginx"`
+}
+
+// aliasTemplate is the page generated at an alias. it's executed with the
+// permalink of the page the alias redirects to
+var aliasTemplate = template.Must(template.New("alias").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.}}</title>
+<link rel="canonical" href="{{.}}">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url={{.}}">
+</head>
+<body>
+<p>This page has moved to <a href="{{.}}">{{.}}</a>.</p>
+</body>
+</html>
+`))
+
+// redirect is an alias of a page
+type redirect struct {
+	// from is the url of the alias and to is the url of the page
+	from string
+	to   string
+}
+
+// aliasName returns the name of the node generated for alias.
+// e.g. /old/post/ is generated at old/post/index.html
+func aliasName(alias string) string {
+	name := strings.TrimPrefix(path.Clean(alias), "/")
+	if path.Ext(name) == ".html" {
+		return name
+	}
+	return path.Join(name, "index.html")
+}
+
+// generateAliases renders a page that redirects to its page at every alias
+// in the front matter of pages, and the redirect files turned on in
+// ssg.toml. aliases that are already used by another file of the site are
+// reported
+func (sb *siteBuilder) generateAliases(
+	site *SiteContext,
+	pages map[string]*PageContext,
+	nodes []Node,
+) ([]Node, error) {
+	var generated []Node
+	var redirects []redirect
+	// the files of the pages that the aliases were generated for
+	used := make(map[string]string)
+
+	for _, file := range slices.Sorted(maps.Keys(pages)) {
+		page := pages[file]
+		for _, alias := range page.Aliases {
+			name := aliasName(alias)
+			other, ok := used[name]
+			if !ok {
+				if node := findNode(nodes, name); node != nil {
+					other, ok = node.Source, true
+					if other == "" {
+						other = node.Name
+					}
+				}
+			}
+			if ok {
+				sb.addError(file, fmt.Errorf("alias %s is already used by %s", alias, other))
+				continue
+			}
+			used[name] = file
+
+			var content strings.Builder
+			err := aliasTemplate.Execute(&content, page.Permalink)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate alias %s: %w", alias, err)
+			}
+			// both urls start with the path of base_url like every other link
+			to := relURL(site.BaseURL, page.RelPermalink)
+			generated = append(generated, Node{
+				Name:     name,
+				Type:     HTMLNode,
+				Content:  []byte(content.String()),
+				Redirect: to,
+			})
+			redirects = append(redirects, redirect{
+				from: relURL(site.BaseURL, strings.TrimSuffix(name, "index.html")),
+				to:   to,
+			})
+		}
+	}
+
+	if len(redirects) == 0 {
+		return generated, nil
+	}
+	slices.SortFunc(redirects, func(a, b redirect) int {
+		return strings.Compare(a.from, b.from)
+	})
+
+	config := site.Config.Redirects
+	if config.Netlify && findNode(nodes, redirectsName) == nil {
+		var b strings.Builder
+		for _, r := range redirects {
+			fmt.Fprintf(&b, "%s %s 301\n", r.from, r.to)
+		}
+		generated = append(generated, Node{
+			Name:    redirectsName,
+			Type:    FileNode,
+			Content: []byte(b.String()),
+		})
+	}
+	if config.Nginx && findNode(nodes, nginxMapName) == nil {
+		var b strings.Builder
+		for _, r := range redirects {
+			fmt.Fprintf(&b, "%s %s;\n", r.from, r.to)
+		}
+		generated = append(generated, Node{
+			Name:    nginxMapName,
+			Type:    FileNode,
+			Content: []byte(b.String()),
+		})
+	}
+	return generated, nil
+}
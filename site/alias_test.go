@@ -0,0 +1,167 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// aliasTestFiles are the pages of the alias tests
+var aliasTestFiles = map[string]string{
+	"content/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\naliases = [\"/content/a.html\", \"/old/a\"]\n+++\n",
+	"content/b.md": "+++\ntitle = \"b\"\ndate = 2024-02-01\naliases = \"/posts/b/\"\n+++\n[a](/content/a.html)",
+}
+
+func TestAliases(t *testing.T) {
+	s, err := BuildFromEntries(siteEntries("base_url = \"https://example.com\"\n", aliasTestFiles), BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		redirect string
+		contains []string
+	}{
+		{
+			"content/a.html",
+			"/content/a/",
+			[]string{
+				`<link rel="canonical" href="https://example.com/content/a/">`,
+				`<meta http-equiv="refresh" content="0; url=https://example.com/content/a/">`,
+			},
+		},
+		{"old/a/index.html", "/content/a/", []string{`href="https://example.com/content/a/"`}},
+		{"posts/b/index.html", "/content/b/", []string{`href="https://example.com/content/b/"`}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			if node.Redirect != tt.redirect {
+				t.Errorf("wrong redirect. expected=%q got=%q", tt.redirect, node.Redirect)
+			}
+			for _, str := range tt.contains {
+				if !bytes.Contains(node.Content, []byte(str)) {
+					t.Errorf("expected %s to contain %s. got=\n%s", tt.name, str, node.Content)
+				}
+			}
+		})
+	}
+
+	sitemap := findNode(s.Nodes, sitemapName)
+	if sitemap == nil {
+		t.Fatal("sitemap.xml wasn't generated")
+	}
+	if bytes.Contains(sitemap.Content, []byte("/old/a/")) {
+		t.Errorf("aliases shouldn't be in the sitemap. got=\n%s", sitemap.Content)
+	}
+	for _, name := range []string{redirectsName, nginxMapName} {
+		if findNode(s.Nodes, name) != nil {
+			t.Errorf("%s shouldn't be generated unless it's turned on", name)
+		}
+	}
+
+	// links to the old urls still work
+	if diagnostics := CheckEntries(siteEntries("", aliasTestFiles), BuildOptions{}); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics. got %v", diagnostics)
+	}
+}
+
+func TestRedirectFiles(t *testing.T) {
+	tests := []struct {
+		config   string
+		name     string
+		expected string
+	}{
+		{
+			"",
+			redirectsName,
+			"/content/a.html /content/a/ 301\n/old/a/ /content/a/ 301\n/posts/b/ /content/b/ 301\n",
+		},
+		{
+			"",
+			nginxMapName,
+			"/content/a.html /content/a/;\n/old/a/ /content/a/;\n/posts/b/ /content/b/;\n",
+		},
+		{
+			"base_url = \"https://example.com/blog/\"\n",
+			redirectsName,
+			"/blog/content/a.html /blog/content/a/ 301\n/blog/old/a/ /blog/content/a/ 301\n/blog/posts/b/ /blog/content/b/ 301\n",
+		},
+		{
+			"base_url = \"https://example.com/blog/\"\n",
+			nginxMapName,
+			"/blog/content/a.html /blog/content/a/;\n/blog/old/a/ /blog/content/a/;\n/blog/posts/b/ /blog/content/b/;\n",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			s, err := BuildFromEntries(
+				siteEntries(tt.config+"[redirects]\nnetlify = true\nnginx = true\n", aliasTestFiles),
+				BuildOptions{},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			if string(node.Content) != tt.expected {
+				t.Errorf("wrong %s.\nexpected=%q\n     got=%q", tt.name, tt.expected, node.Content)
+			}
+		})
+	}
+}
+
+func TestAliasBasePath(t *testing.T) {
+	s, err := BuildFromEntries(
+		siteEntries("base_url = \"https://example.com/blog/\"\n", aliasTestFiles),
+		BuildOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := findNode(s.Nodes, "old/a/index.html")
+	if node == nil {
+		t.Fatal("old/a/index.html wasn't generated")
+	}
+	if node.Redirect != "/blog/content/a/" {
+		t.Errorf("wrong redirect. expected=%q got=%q", "/blog/content/a/", node.Redirect)
+	}
+	expected := `<meta http-equiv="refresh" content="0; url=https://example.com/blog/content/a/">`
+	if !bytes.Contains(node.Content, []byte(expected)) {
+		t.Errorf("expected old/a/index.html to contain %s. got=\n%s", expected, node.Content)
+	}
+}
+
+func TestAliasCollisions(t *testing.T) {
+	entries := siteEntries("", map[string]string{
+		"old.html":     "mine",
+		"content/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\naliases = [\"/old.html\", \"/moved/\"]\n+++\n",
+		"content/b.md": "+++\ntitle = \"b\"\ndate = 2024-02-01\naliases = [\"/moved\", \"/content/a/\"]\n+++\n",
+	})
+
+	s, sb := buildWithDiagnostics(entries, BuildOptions{})
+	expected := Diagnostics{
+		{File: "content/a.md", Severity: SeverityError, Message: "alias /old.html is already used by old.html"},
+		{File: "content/b.md", Severity: SeverityError, Message: "alias /moved is already used by content/a.md"},
+		{File: "content/b.md", Severity: SeverityError, Message: "alias /content/a/ is already used by content/a.md"},
+	}
+	if fmt.Sprint(sb.diagnostics) != fmt.Sprint(expected) {
+		t.Errorf("wrong diagnostics.\nexpected=%v\n     got=%v", expected, sb.diagnostics)
+	}
+
+	if node := findNode(s.Nodes, "old.html"); node == nil || string(node.Content) != "mine" {
+		t.Errorf("old.html shouldn't be replaced by an alias")
+	}
+	if node := findNode(s.Nodes, "moved/index.html"); node == nil || node.Redirect != "/content/a/" {
+		t.Errorf("/moved/ should redirect to content/a.md")
+	}
+}
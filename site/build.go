@@ -3,40 +3,216 @@ package site
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// BuildMarker is written to every directory created by BuildSite.
+// BuildSite refuses to replace a directory that isn't empty and doesn't
+// contain it.
+const BuildMarker = ".ssg-build"
+
+const buildMarkerContent = "this directory was created by ssg and is replaced on every build\n"
+
+// BuildSite writes s into dir.
+// the site is first written into a temporary directory next to dir which
+// then replaces dir, so dir is left untouched if anything fails.
+// hidden files in dir (like .git) are kept
 func BuildSite(s Site, dir string) error {
-	err := createIfNotExists(dir)
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	err = checkBuildDirectory(absDir, s.Dir)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(
+		filepath.Dir(absDir),
+		"."+filepath.Base(absDir)+"-tmp-",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = os.Chmod(tmpDir, 0755)
 	if err != nil {
 		return err
 	}
 
-	err = clearDirectory(dir)
+	err = os.WriteFile(
+		filepath.Join(tmpDir, BuildMarker),
+		[]byte(buildMarkerContent),
+		0644,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to clear files from %s: %w", dir, err)
+		return fmt.Errorf("failed to write %s: %w", BuildMarker, err)
 	}
 
 	for _, node := range s.Nodes {
-		err = writeNode(dir, node)
+		err = writeNode(tmpDir, node)
 		if err != nil {
 			return fmt.Errorf("failed to write node: %s: %w", node.Name, err)
 		}
 	}
 
+	err = replaceDirectory(absDir, tmpDir)
+	if err != nil {
+		return fmt.Errorf("failed to replace %s: %w", dir, err)
+	}
+
 	return nil
 }
 
+// checkBuildDirectory returns an error if dir can't be replaced by a build.
+// dir can't be the site's directory or any of its parents and if it exists
+// it has to be empty or contain the BuildMarker.
+// siteDir is ignored if it is empty
+func checkBuildDirectory(dir, siteDir string) error {
+	if siteDir != "" {
+		absSiteDir, err := filepath.Abs(siteDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", siteDir, err)
+		}
+
+		if isParentOrSame(dir, absSiteDir) {
+			return fmt.Errorf(
+				"refusing to build into %s because it contains the site's files",
+				dir,
+			)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", dir, err)
+	}
+
+	if len(entries) == 0 || isBuildDirectory(dir) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"refusing to replace %s because it wasn't created by ssg (no %s file). "+
+			"remove it or choose another build directory",
+		dir,
+		BuildMarker,
+	)
+}
+
+// reports whether parent is child or one of child's parents
+func isParentOrSame(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	if err != nil {
+		return false
+	}
+	if rel == "." {
+		return true
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func isBuildDirectory(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, BuildMarker))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// replaceDirectory replaces dir with newDir. hidden files in dir are moved
+// into newDir first. if anything fails dir is restored.
+func replaceDirectory(dir, newDir string) error {
+	_, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return os.Rename(newDir, dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	oldDir, err := os.MkdirTemp(
+		filepath.Dir(dir),
+		"."+filepath.Base(dir)+"-old-",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	// MkdirTemp is only used to get a unique name
+	err = os.Remove(oldDir)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(dir, oldDir)
+	if err != nil {
+		return err
+	}
+
+	moved, err := moveHiddenFiles(oldDir, newDir)
+	if err == nil {
+		err = os.Rename(newDir, dir)
+	}
+	if err != nil {
+		for _, name := range moved {
+			os.Rename(filepath.Join(newDir, name), filepath.Join(oldDir, name))
+		}
+		if restoreErr := os.Rename(oldDir, dir); restoreErr != nil {
+			return fmt.Errorf(
+				"%w. failed to restore the previous build from %s: %w",
+				err,
+				oldDir,
+				restoreErr,
+			)
+		}
+		return err
+	}
+
+	return os.RemoveAll(oldDir)
+}
+
+// moves the hidden files of src except the BuildMarker into dst.
+// returns the names of the moved files
+func moveHiddenFiles(src, dst string) ([]string, error) {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadDir failed: %w", err)
+	}
+
+	var moved []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, ".") || name == BuildMarker {
+			continue
+		}
+
+		err = os.Rename(filepath.Join(src, name), filepath.Join(dst, name))
+		if err != nil {
+			return moved, err
+		}
+		moved = append(moved, name)
+	}
+
+	return moved, nil
+}
+
 func writeNode(dir string, node Node) error {
+	if !IsPublished(node) {
+		return nil
+	}
+
 	switch node.Type {
 
 	case HTMLNode:
 		return os.WriteFile(filepath.Join(dir, node.Name), node.Content, 0644)
 	case FileNode:
-		if !strings.HasSuffix(node.Name, ".css") {
-			return nil
+		if node.Path != "" {
+			return copyFile(filepath.Join(dir, node.Name), node.Path, node.Mode)
 		}
 		return os.WriteFile(filepath.Join(dir, node.Name), node.Content, 0644)
 
@@ -57,24 +233,34 @@ func writeNode(dir string, node Node) error {
 	return nil
 }
 
-func clearDirectory(dir string) error {
-	entries, err := os.ReadDir(dir)
+// copyFile streams src into dst. dst keeps the permissions of mode
+// as long as the file is readable by everyone
+func copyFile(dst, src string, mode os.FileMode) error {
+	in, err := os.Open(src)
 	if err != nil {
-		return fmt.Errorf("os.ReadDir failed: %w", err)
+		return err
 	}
+	defer in.Close()
 
-	for _, entry := range entries {
-		if strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
+	perm := mode.Perm() | 0644
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
 
-		err = os.RemoveAll(filepath.Join(dir, entry.Name()))
-		if err != nil {
-			return fmt.Errorf("os.Remove failed: %w", err)
-		}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
 	}
 
-	return nil
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+
+	// the umask might have removed some of the permissions
+	return os.Chmod(dst, perm)
 }
 
 func createIfNotExists(dir string) error {
@@ -11,7 +11,13 @@ import (
 func TestBuildSite(t *testing.T) {
 	outDir := t.TempDir()
 
-	err := os.Mkdir(filepath.Join(outDir, ".git"), 0755)
+	// outDir was created by a previous build
+	err := os.WriteFile(filepath.Join(outDir, BuildMarker), nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Mkdir(filepath.Join(outDir, ".git"), 0755)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,12 +158,14 @@ func TestBuildSite(t *testing.T) {
 	}
 
 	files := []string{
+		BuildMarker,
 		".git",
 		".git/gitfile.txt",
 		".git/gitfile2.txt",
 		"index.html",
 		"content",
-		"content/inner.html",
+		"content/inner",
+		"content/inner/index.html",
 		"content/index.html",
 		"themes",
 		"themes/dark.css",
@@ -202,13 +210,18 @@ func TestBuildSite(t *testing.T) {
 }
 
 func markdownHeader() string {
-	return "+++\ntitle=blog\n+++\n"
+	return "+++\ntitle=blog\ndate=01-01-2000\n+++\n"
 }
 
 func TestBuildSiteManual(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	err := os.WriteFile(
+	err := os.WriteFile(filepath.Join(tmpDir, BuildMarker), nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(
 		filepath.Join(tmpDir, "remove.html"),
 		[]byte("foo"),
 		0644,
@@ -318,6 +331,10 @@ func TestBuildSiteManual(t *testing.T) {
 	}
 
 	files := []File{
+		{
+			name:    BuildMarker,
+			content: buildMarkerContent,
+		},
 		{
 			name: ".git",
 		},
@@ -388,3 +405,203 @@ func flattenNodes(nodes []Node) []Node {
 	}
 	return flat
 }
+
+func TestBuildSiteStaticFiles(t *testing.T) {
+	siteDir := t.TempDir()
+	outDir := t.TempDir()
+
+	files := []struct {
+		name    string
+		content string
+		mode    os.FileMode
+	}{
+		{"ssg.toml", defaultSsgToml(), 0644},
+		{"themes/dark.css", defaultDarkTheme(), 0644},
+		{"static/image.png", "png", 0644},
+		{"static/fonts/font.woff2", "woff2", 0644},
+		{"static/script.sh", "#!/bin/sh", 0755},
+		{"favicon.ico", "ico", 0600},
+	}
+
+	for _, file := range files {
+		path := filepath.Join(siteDir, file.name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(file.content), file.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.Chmod(path, file.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	site, err := Build(siteDir, BuildOptions{})
+	if err != nil {
+		t.Fatalf("failed to build site: %v", err)
+	}
+
+	err = BuildSite(site, outDir)
+	if err != nil {
+		t.Fatalf("failed to build site: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "ssg.toml")); err == nil {
+		t.Errorf("ssg.toml should not be in the build directory")
+	}
+
+	expectedModes := map[string]os.FileMode{
+		"themes/dark.css":         0644,
+		"static/image.png":        0644,
+		"static/fonts/font.woff2": 0644,
+		"static/script.sh":        0755,
+		// files are always readable by everyone
+		"favicon.ico": 0644,
+	}
+
+	for _, file := range files[1:] {
+		path := filepath.Join(outDir, file.name)
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s was not written: %v", file.name, err)
+			continue
+		}
+		if string(b) != file.content {
+			t.Errorf(
+				"wrong content for %s. expected=%q. got=%q",
+				file.name,
+				file.content,
+				string(b),
+			)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != expectedModes[file.name] {
+			t.Errorf(
+				"wrong mode for %s. expected=%v. got=%v",
+				file.name,
+				expectedModes[file.name],
+				info.Mode().Perm(),
+			)
+		}
+	}
+}
+
+func TestBuildSiteRefusesToReplace(t *testing.T) {
+	siteDir := filepath.Join(t.TempDir(), "site")
+	err := os.MkdirAll(filepath.Join(siteDir, "content"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(
+		filepath.Join(siteDir, "content", "post.md"),
+		[]byte("post"),
+		0644,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notOurs := t.TempDir()
+	err = os.WriteFile(filepath.Join(notOurs, "file.txt"), []byte("keep"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	site := Site{
+		Nodes: []Node{{Name: "index.html", Type: HTMLNode, Content: []byte("index")}},
+		Dir:   siteDir,
+	}
+
+	dirs := []string{
+		siteDir,
+		filepath.Dir(siteDir),
+		filepath.Join(siteDir, "content"),
+		notOurs,
+	}
+
+	for _, dir := range dirs {
+		t.Run(dir, func(t *testing.T) {
+			err := BuildSite(site, dir)
+			if err == nil {
+				t.Fatalf("expected BuildSite to refuse to replace %s", dir)
+			}
+		})
+	}
+
+	b, err := os.ReadFile(filepath.Join(siteDir, "content", "post.md"))
+	if err != nil || string(b) != "post" {
+		t.Errorf("content/post.md was modified: %v", err)
+	}
+
+	b, err = os.ReadFile(filepath.Join(notOurs, "file.txt"))
+	if err != nil || string(b) != "keep" {
+		t.Errorf("file.txt was modified: %v", err)
+	}
+
+	// a build directory inside the site is fine
+	buildDir := filepath.Join(siteDir, "ssg-build")
+	err = BuildSite(site, buildDir)
+	if err != nil {
+		t.Fatalf("failed to build site: %v", err)
+	}
+
+	entries, err := loadDirectoryEntries(siteDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasEntry(entries, "ssg-build") {
+		t.Errorf("the build directory shouldn't be loaded as part of the site")
+	}
+}
+
+func TestBuildSiteFailureKeepsPreviousBuild(t *testing.T) {
+	outDir := t.TempDir()
+
+	previous := Site{
+		Nodes: []Node{{Name: "index.html", Type: HTMLNode, Content: []byte("old")}},
+	}
+	err := BuildSite(previous, outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broken := Site{
+		Nodes: []Node{
+			{Name: "index.html", Type: HTMLNode, Content: []byte("new")},
+			{
+				Name: "image.png",
+				Type: FileNode,
+				Path: filepath.Join(t.TempDir(), "dne.png"),
+			},
+		},
+	}
+	err = BuildSite(broken, outDir)
+	if err == nil {
+		t.Fatal("expected BuildSite to fail")
+	}
+
+	b, err := os.ReadFile(filepath.Join(outDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "old" {
+		t.Errorf("previous build was modified. got=%q", string(b))
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(outDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), "-tmp-") {
+			t.Errorf("temporary directory %s wasn't removed", entry.Name())
+		}
+	}
+}
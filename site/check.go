@@ -0,0 +1,194 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// names that can't be used at the root of a site because the
+// development server uses them
+var reservedNames = []string{"fsevents"}
+
+func IsReserved(name string) bool {
+	for _, reserved := range reservedNames {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// Check runs the same pipeline as Build and reports every diagnostic it
+// finds. it also reports reserved names and internal links that don't point
+// to anything in the site.
+// the returned error is only set if the site couldn't be read
+func Check(dir string, opts BuildOptions) (Diagnostics, error) {
+	entries, err := loadDirectoryEntries(dir)
+	if err != nil {
+		return nil, err
+	}
+	return CheckEntries(entries, opts), nil
+}
+
+func CheckEntries(entries []Entry, opts BuildOptions) Diagnostics {
+	s, sb := buildWithDiagnostics(entries, opts)
+	diagnostics := sb.diagnostics
+
+	for _, node := range s.Nodes {
+		if IsReserved(node.Name) {
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     reservedFile(entries, node),
+				Severity: SeverityError,
+				Message: fmt.Sprintf(
+					"/%s is reserved for the server, choose another name",
+					node.Name,
+				),
+			})
+		}
+	}
+
+	// pages that failed to build are treated as existing so that links
+	// to them aren't reported twice
+	failed := make([]Node, len(sb.failedNodes))
+	for i, name := range sb.failedNodes {
+		failed[i] = Node{Name: name, Type: HTMLNode}
+	}
+	root := append(s.Nodes[:len(s.Nodes):len(s.Nodes)], failed...)
+
+	diagnostics = append(diagnostics, checkLinks(root, s.Nodes, basePath(s.Config.BaseURL))...)
+
+	return diagnostics
+}
+
+// reservedFile returns the file that the reserved node was built from.
+// directories of pages are rebuilt without a source so it's the entry
+// with the same name or the page at the url of the directory
+func reservedFile(entries []Entry, node Node) string {
+	if node.Source != "" {
+		return node.Source
+	}
+	if hasEntry(entries, node.Name) {
+		return node.Name
+	}
+	if index := findNode(node.Children, node.Name+"/index.html"); index != nil && index.Source != "" {
+		return index.Source
+	}
+	return node.Name
+}
+
+// links that start with base, the path of base_url, are relative to the
+// root of the site
+func checkLinks(root, nodes []Node, base string) Diagnostics {
+	var diagnostics Diagnostics
+
+	for _, node := range nodes {
+		if node.Type == DirectoryNode {
+			diagnostics = append(diagnostics, checkLinks(root, node.Children, base)...)
+			continue
+		}
+		if node.Type != HTMLNode {
+			continue
+		}
+
+		for _, link := range findLinks(node.Content) {
+			target, ok := internalLinkPath(node.Name, link, base)
+			if !ok {
+				continue
+			}
+			if !pathExists(root, target) {
+				file := node.Source
+				if file == "" {
+					file = node.Name
+				}
+				diagnostics = append(diagnostics, Diagnostic{
+					File:     file,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("broken link %s", link),
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// returns the href and src attributes of every element in doc
+func findLinks(doc []byte) []string {
+	var links []string
+
+	z := html.NewTokenizer(bytes.NewReader(doc))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			for {
+				key, val, more := z.TagAttr()
+				if string(key) == "href" || string(key) == "src" {
+					links = append(links, string(val))
+				}
+				if !more {
+					break
+				}
+			}
+		}
+	}
+}
+
+// resolves link relative to the node named from. base is removed from
+// the start of paths. returns false if link points outside of the site
+func internalLinkPath(from, link, base string) (string, bool) {
+	if link == "" || strings.HasPrefix(link, "#") {
+		return "", false
+	}
+
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Opaque != "" {
+		return "", false
+	}
+	if u.Path == "" {
+		return "", false
+	}
+
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join("/", path.Dir(from), p)
+	} else if base != "" && (p == base || strings.HasPrefix(p, base+"/")) {
+		p = strings.TrimPrefix(p, base)
+	}
+	return strings.Trim(path.Clean(p), "/"), true
+}
+
+// name follows the same rules as the development server.
+// a directory only exists if it has an index.html
+func pathExists(nodes []Node, name string) bool {
+	if name == "" {
+		return findNode(nodes, "index.html") != nil
+	}
+
+	node := findNode(nodes, name)
+	if node == nil {
+		return false
+	}
+	if node.Type == DirectoryNode {
+		return findNode(node.Children, name+"/index.html") != nil
+	}
+	return true
+}
+
+func findNode(nodes []Node, name string) *Node {
+	for i := range nodes {
+		if nodes[i].Name == name {
+			return &nodes[i]
+		}
+		if n := findNode(nodes[i].Children, name); n != nil {
+			return n
+		}
+	}
+	return nil
+}
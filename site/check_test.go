@@ -0,0 +1,193 @@
+package site
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCheckEntries(t *testing.T) {
+	validPost := "+++\ntitle = post\ndate = 01-01-2000\n+++\n"
+
+	tests := []struct {
+		entries  []Entry
+		expected Diagnostics
+	}{
+		{
+			[]Entry{
+				defaultSsgTomlEntry(),
+				defaultThemeDirEntry(),
+				&testEntry{
+					name: "content",
+					typ:  DirectoryEntry,
+					children: []Entry{
+						&testEntry{
+							name:    "content/a.md",
+							typ:     FileEntry,
+							content: validPost + "[b](/content/b/) [root](/) [ext](https://example.com)",
+						},
+						&testEntry{
+							name:    "content/b.md",
+							typ:     FileEntry,
+							content: validPost + "[a](../a/) [top](#top) [theme](../../themes/dark.css)",
+						},
+						&testEntry{
+							name:    "content/yaml.md",
+							typ:     FileEntry,
+							content: "---\ntitle: yaml\ndate: 2000-01-01\ntags: [a, b]\n---\n[b](../b/)",
+						},
+						&testEntry{
+							name:    "content/json.md",
+							typ:     FileEntry,
+							content: "{\"title\": \"json\", \"date\": \"01-01-2000\"}\n[b](../b/)",
+						},
+						&testEntry{
+							name:    "content/toml.md",
+							typ:     FileEntry,
+							content: "+++\ntitle = \"a = b\"\ndate = 2000-01-01\n[cover]\nimage = \"c.png\"\n+++\n[b](../b/)",
+						},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			[]Entry{
+				defaultSsgTomlEntry(),
+				defaultThemeDirEntry(),
+				&testEntry{
+					name: "content",
+					typ:  DirectoryEntry,
+					children: []Entry{
+						&testEntry{
+							name:    "content/no-title.md",
+							typ:     FileEntry,
+							content: "+++\ndate = 01-01-2000\n+++\n",
+						},
+						&testEntry{
+							name:    "content/no-date.md",
+							typ:     FileEntry,
+							content: "+++\ntitle = post\n+++\n",
+						},
+						&testEntry{
+							name:    "content/bad-draft.md",
+							typ:     FileEntry,
+							content: "+++\ntitle = post\ndate = 01-01-2000\ndraft = maybe\n+++\n",
+						},
+						&testEntry{
+							name:    "content/bad-metadata.md",
+							typ:     FileEntry,
+							content: "+++\ntitle: post\n+++\n",
+						},
+						&testEntry{
+							name:    "content/links.md",
+							typ:     FileEntry,
+							content: validPost + "[dne](/dne.html) [static](/static/img.png)",
+						},
+					},
+				},
+				&testEntry{
+					name: "fsevents",
+					typ:  DirectoryEntry,
+					children: []Entry{
+						&testEntry{
+							name:    "fsevents/file.txt",
+							typ:     FileEntry,
+							content: "text",
+						},
+					},
+				},
+			},
+			Diagnostics{
+				{"content/no-title.md", 0, 0, SeverityError, "blog title not found"},
+				{"content/no-date.md", 0, 0, SeverityError, "blog date not found"},
+				{"content/bad-draft.md", 0, 0, SeverityError, "Invalid value for draft maybe. expected true or false"},
+				{"content/bad-metadata.md", 2, 6, SeverityError, "invalid metadata: expected = after the key title"},
+				{"fsevents", 0, 0, SeverityError, "/fsevents is reserved for the server, choose another name"},
+				{"content/links.md", 0, 0, SeverityError, "broken link /dne.html"},
+				{"content/links.md", 0, 0, SeverityError, "broken link /static/img.png"},
+			},
+		},
+		{
+			[]Entry{
+				&testEntry{
+					name:    "ssg.toml",
+					typ:     FileEntry,
+					content: "title = \"test blog\"\nauthor = \"test author\"\ntheme = \"dne\"\n",
+				},
+				defaultThemeDirEntry(),
+				&testEntry{
+					name:    "post.md",
+					typ:     FileEntry,
+					content: "+++\ntitle = post\n+++\n",
+				},
+			},
+			Diagnostics{
+				{"ssg.toml", 0, 0, SeverityError, "failed to parse config: theme dne not found in themes/"},
+				{"post.md", 0, 0, SeverityError, "blog date not found"},
+			},
+		},
+		{
+			[]Entry{
+				&testEntry{
+					name:    "ssg.toml",
+					typ:     FileEntry,
+					content: "title = \"test blog\"\n\nauthor = test author\n",
+				},
+				defaultThemeDirEntry(),
+				&testEntry{
+					name:    "post.md",
+					typ:     FileEntry,
+					content: "+++\ntitle = post\ndate = 01-01-2000\n+++\n[dne](dne.html)",
+				},
+			},
+			Diagnostics{
+				{"ssg.toml", 3, 10, SeverityError, "invalid value test. strings have to be quoted"},
+				// the site title links to / and there is no index page
+				{"post.md", 0, 0, SeverityError, "broken link /"},
+				{"post.md", 0, 0, SeverityError, "broken link dne.html"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			diagnostics := CheckEntries(tt.entries, BuildOptions{})
+			if !slices.Equal(diagnostics, tt.expected) {
+				t.Errorf(
+					"wrong diagnostics.\nexpected=%v\n     got=%v",
+					tt.expected,
+					diagnostics,
+				)
+			}
+		})
+	}
+}
+
+func TestReservedNames(t *testing.T) {
+	post := "+++\ntitle = \"post\"\ndate = 2000-01-01\n+++\n"
+	message := "/fsevents is reserved for the server, choose another name"
+
+	tests := []struct {
+		files    map[string]string
+		expected string
+	}{
+		{map[string]string{"fsevents/file.txt": "text"}, "fsevents"},
+		{map[string]string{"fsevents/post.md": post}, "fsevents"},
+		{map[string]string{"fsevents.md": post}, "fsevents.md"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			// the site title links to the index page
+			entries := addFileEntries(siteEntries("", tt.files), map[string]string{
+				"index.md": "+++\ntitle = \"home\"\n+++\n",
+			})
+			diagnostics := CheckEntries(entries, BuildOptions{})
+			expected := Diagnostics{{File: tt.expected, Severity: SeverityError, Message: message}}
+			if !slices.Equal(diagnostics, expected) {
+				t.Errorf("wrong diagnostics.\nexpected=%v\n     got=%v", expected, diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package site
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"html/template"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Context is the data that layouts are executed with
+type Context struct {
+	Site *SiteContext
+	// Page is the page being rendered
+	Page *PageContext
+	// Pages are the pages listed by list layouts like index.html.
+	// only the ones on this page if the list is paginated.
+	// nil for single pages
+	Pages []*PageContext
+	// Paginator is the page of the list being rendered.
+	// nil for single pages
+	Paginator *Paginator
+	// Section is the section listed by section.html. it's content/ in
+	// index.html. nil for every other layout
+	Section *Section
+	// Terms are the terms of the taxonomy listed by taxonomy.html
+	Terms []Term
+	// Feeds are the feeds of the list being rendered. e.g. the feed of a
+	// term. they're linked to along with .Site.Feeds
+	Feeds []FeedLink
+}
+
+// SiteContext is the same for every page of a site
+type SiteContext struct {
+	Config SiteConfig
+	Title  string
+	Author string
+	// Theme is the path of the theme's stylesheet
+	Theme      string
+	BaseURL    string
+	DateFormat string
+	// Pages has every page of the site, newest first
+	Pages []*PageContext
+	// Sections maps top level directories like content to their pages.
+	// pages at the root of the site aren't in a section
+	Sections map[string][]*PageContext
+	// Taxonomies maps a taxonomy like tags to its terms and their pages.
+	// every taxonomy of the site is in it even if no page uses it
+	Taxonomies map[string]map[string][]*PageContext
+	// Menus maps the name of a menu like main to its items
+	Menus  map[string][]MenuItem
+	Social []SocialLink
+	// Feeds are the feeds that are generated for the site.
+	// empty if base_url isn't set
+	Feeds     []FeedLink
+	BuildTime time.Time
+	// Environment is development when the site is served by the
+	// development server and production otherwise
+	Environment string
+}
+
+// PageContext is a page with everything that's known about it once
+// every page of the site is built
+type PageContext struct {
+	*Page
+	// File is the path of the page's markdown file. e.g. content/a.md
+	File    string
+	Content template.HTML
+	// Summary is the page's description or its first paragraph
+	Summary      template.HTML
+	RelPermalink string
+	// Permalink is RelPermalink with the site's base_url
+	Permalink string
+	// Section is the top level directory of the page.
+	// empty for pages at the root of the site
+	Section string
+	// Prev is the page in the same section that was published before this
+	// one and Next is the one published after it. nil if there isn't one
+	Prev *PageContext
+	Next *PageContext
+}
+
+const (
+	development = "development"
+	production  = "production"
+)
+
+// newSiteContext creates the context of every page in nodes.
+// the returned map has the context of every page by its markdown file
+func newSiteContext(
+	config SiteConfig,
+	nodes []Node,
+) (*SiteContext, map[string]*PageContext) {
+	site := &SiteContext{
+		Config:      config,
+		Title:       config.Title,
+		Author:      config.Author,
+		Theme:       config.Theme,
+		BaseURL:     config.BaseURL,
+		DateFormat:  config.DateFormat,
+		Social:      config.Social,
+		Sections:    make(map[string][]*PageContext),
+		Taxonomies:  make(map[string]map[string][]*PageContext),
+		BuildTime:   time.Now(),
+		Environment: production,
+	}
+	if config.EnableHotReloading {
+		site.Environment = development
+	}
+	for _, taxonomy := range config.taxonomies() {
+		site.Taxonomies[taxonomy] = make(map[string][]*PageContext)
+	}
+
+	pages := make(map[string]*PageContext)
+	var collect func(nodes []Node)
+	collect = func(nodes []Node) {
+		for _, node := range nodes {
+			if node.Type == DirectoryNode {
+				collect(node.Children)
+				continue
+			}
+			if node.Page == nil || node.Type != HTMLNode {
+				continue
+			}
+
+			relPermalink := "/" + node.Name
+			if path.Base(node.Name) == "index.html" {
+				relPermalink = "/" + strings.TrimSuffix(node.Name, "index.html")
+			}
+			page := &PageContext{
+				Page:         node.Page,
+				File:         node.Source,
+				Content:      template.HTML(node.Content),
+				Summary:      summary(node.Page, node.Content),
+				RelPermalink: relPermalink,
+				Permalink:    absURL(config.BaseURL, relPermalink),
+				Section:      section(node.Source),
+			}
+			pages[node.Source] = page
+			// index pages list other pages instead of being listed
+			if !isIndexPage(node.Source) {
+				site.Pages = append(site.Pages, page)
+			}
+		}
+	}
+	collect(nodes)
+
+	slices.SortStableFunc(site.Pages, func(a, b *PageContext) int {
+		if c := b.Date.Compare(a.Date); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.RelPermalink, b.RelPermalink)
+	})
+
+	for _, page := range site.Pages {
+		if page.Section != "" {
+			site.Sections[page.Section] = append(site.Sections[page.Section], page)
+		}
+		for taxonomy, terms := range site.Taxonomies {
+			for _, term := range page.Terms(taxonomy) {
+				terms[term] = append(terms[term], page)
+			}
+		}
+	}
+
+	// sections are newest first
+	for _, sectionPages := range site.Sections {
+		for i, page := range sectionPages {
+			if i > 0 {
+				page.Next = sectionPages[i-1]
+			}
+			if i < len(sectionPages)-1 {
+				page.Prev = sectionPages[i+1]
+			}
+		}
+	}
+
+	return site, pages
+}
+
+// section returns the top level directory of name
+func section(name string) string {
+	dir, _, ok := strings.Cut(name, "/")
+	if !ok {
+		return ""
+	}
+	return dir
+}
+
+func summary(page *Page, content []byte) template.HTML {
+	if page.Description != "" {
+		return template.HTML(template.HTMLEscapeString(page.Description))
+	}
+
+	start := bytes.Index(content, []byte("<p>"))
+	if start == -1 {
+		return ""
+	}
+	end := bytes.Index(content[start:], []byte("</p>"))
+	if end == -1 {
+		return ""
+	}
+	return template.HTML(content[start : start+end+len("</p>")])
+}
+
+// renderLayout executes layout with ctx
+func renderLayout(layout *template.Template, ctx Context) ([]byte, error) {
+	var buf bytes.Buffer
+	err := layout.Execute(&buf, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute the %s layout: %w", layout.Name(), err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+// titles joins the titles of pages with spaces
+func titles(pages []*PageContext) string {
+	s := make([]string, len(pages))
+	for i, page := range pages {
+		s[i] = page.Title
+	}
+	return strings.Join(s, " ")
+}
+
+func TestSiteContext(t *testing.T) {
+	entries := siteEntries("", map[string]string{
+		"content/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\ntags = [\"go\"]\n+++\nfirst paragraph\n\nsecond",
+		"content/b.md": "+++\ntitle = \"b\"\ndate = 2024-02-01\ndescription = \"about <b>\"\n+++\nhello",
+		"content/c.md": "+++\ntitle = \"c\"\ndate = 2024-03-01\ntags = [\"go\", \"web\"]\n+++\nhello",
+		"about.md":     "+++\ntitle = \"about\"\ndate = 2023-01-01\n+++\nabout",
+	})
+
+	sb, err := newSiteBuilder(entries, BuildOptions{EnableHotReloading: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	site, pages := newSiteContext(sb.config, sb.buildNodes(entries))
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"pages", titles(site.Pages), "c b a about"},
+		{"sections", fmt.Sprint(len(site.Sections)), "1"},
+		{"content section", titles(site.Sections["content"]), "c b a"},
+		{"go tag", titles(site.Taxonomies["tags"]["go"]), "c a"},
+		{"web tag", titles(site.Taxonomies["tags"]["web"]), "c"},
+		{"environment", site.Environment, development},
+		{"section", pages["content/a.md"].Section, "content"},
+		{"root section", pages["about.md"].Section, ""},
+		{"permalink", pages["content/a.md"].Permalink, "/content/a/"},
+		{"summary", string(pages["content/a.md"].Summary), "<p>first paragraph</p>"},
+		{"description summary", string(pages["content/b.md"].Summary), "about &lt;b&gt;"},
+		{"next", pages["content/b.md"].Next.Title, "c"},
+		{"prev", pages["content/b.md"].Prev.Title, "a"},
+		{"no next", fmt.Sprint(pages["content/c.md"].Next == nil), "true"},
+		{"no prev", fmt.Sprint(pages["about.md"].Prev == nil), "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("expected=%q. got=%q", tt.expected, tt.got)
+			}
+		})
+	}
+}
+
+func TestLayoutContext(t *testing.T) {
+	blog := `{{.Page.Title}} by {{.Site.Author}} at {{.Page.Permalink}}` +
+		`{{with .Page.Prev}} prev={{.Title}}{{end}}` +
+		`{{range first 1 (where .Site.Pages "Tags" "go")}} latest={{.Title}}{{end}}` +
+		` {{dateFormat .Site.DateFormat .Page.Date}} {{absURL "x.css"}}`
+
+	entries := siteEntries(
+		"base_url = \"https://example.com\"\ndate_format = \"2006/01/02\"\n",
+		map[string]string{
+			"layouts/blog.html": blog,
+			"content/a.md":      "+++\ntitle = \"a\"\ndate = 2024-01-01\ntags = [\"go\"]\n+++\n",
+			"content/b.md":      "+++\ntitle = \"b\"\ndate = 2024-02-01\n+++\n",
+		},
+	)
+
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"content/a/index.html": "a by test author at https://example.com/content/a/ latest=a 2024/01/01 https://example.com/x.css",
+		"content/b/index.html": "b by test author at https://example.com/content/b/ prev=a latest=a 2024/02/01 https://example.com/x.css",
+	}
+	for name, content := range expected {
+		node := findNode(s.Nodes, name)
+		if node == nil {
+			t.Errorf("%s wasn't built", name)
+			continue
+		}
+		if got := string(node.Content); got != content {
+			t.Errorf("wrong %s.\nexpected=%q\n     got=%q", name, content, got)
+		}
+	}
+}
+
+func TestIndexContext(t *testing.T) {
+	index := `{{.Page.Title}}:{{range .Pages}} {{.Title}} {{.RelPermalink}}{{end}}`
+	entries := siteEntries("", map[string]string{
+		"layouts/index.html": index,
+		"content/a.md":       "+++\ntitle = \"a\"\ndate = 2024-01-01\n+++\n",
+		"content/b.md":       "+++\ntitle = \"b\"\ndate = 2024-02-01\n+++\n",
+	})
+
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := template.HTML("test blog: b /content/b/ a /content/a/")
+	for _, node := range s.Nodes {
+		if node.Name == "index.html" {
+			if got := template.HTML(node.Content); got != expected {
+				t.Errorf("wrong index.\nexpected=%q\n     got=%q", expected, got)
+			}
+			return
+		}
+	}
+	t.Errorf("index.html wasn't generated")
+}
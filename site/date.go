@@ -0,0 +1,81 @@
+package site
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
+)
+
+// DateLayout is the format dates are displayed in if ssg.toml doesn't set
+// date_format. it's also accepted in front matter
+const DateLayout = "02-01-2006"
+
+// layouts of the dates accepted in front matter. they're tried in order
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	time.DateOnly,
+	"2006/01/02",
+	DateLayout,
+	"02-01-2006 15:04",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// parseDate parses a date from the front matter. it's either a date or a
+// string in one of dateLayouts. dates without a time zone are in loc
+func parseDate(value any, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch date := value.(type) {
+	case time.Time:
+		switch date.Location() {
+		case toml.LocalDate, toml.LocalDatetime:
+			return time.Date(
+				date.Year(),
+				date.Month(),
+				date.Day(),
+				date.Hour(),
+				date.Minute(),
+				date.Second(),
+				date.Nanosecond(),
+				loc,
+			), nil
+		case toml.LocalTime:
+			return time.Time{}, fmt.Errorf("%s is a time without a date", date.Format(time.TimeOnly))
+		}
+		return date, nil
+
+	case string:
+		for _, layout := range dateLayouts {
+			t, err := time.ParseInLocation(layout, date, loc)
+			if err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf(
+			"%q is not a date. use a format like 2006-01-02 or 2006-01-02T15:04:05Z07:00",
+			date,
+		)
+	}
+	return time.Time{}, fmt.Errorf("%v is not a date", value)
+}
+
+// formatDate formats t with layout. DateLayout is used if layout is empty
+func formatDate(t time.Time, layout string) string {
+	if layout == "" {
+		layout = DateLayout
+	}
+	return t.Format(layout)
+}
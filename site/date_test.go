@@ -0,0 +1,101 @@
+package site
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
+)
+
+func TestParseDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	plus2 := time.FixedZone("", 2*60*60)
+
+	tests := []struct {
+		value    any
+		loc      *time.Location
+		expected time.Time
+		err      error
+	}{
+		{"2025-08-15", time.UTC, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), nil},
+		{"2025-08-15", berlin, time.Date(2025, 8, 15, 0, 0, 0, 0, berlin), nil},
+		{"15-08-2025", time.UTC, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), nil},
+		{"2025/08/15", time.UTC, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), nil},
+		{"2025-08-15T10:30:00+02:00", time.UTC, time.Date(2025, 8, 15, 10, 30, 0, 0, plus2), nil},
+		{"2025-08-15T10:30:00Z", berlin, time.Date(2025, 8, 15, 10, 30, 0, 0, time.UTC), nil},
+		{"2025-08-15 10:30", berlin, time.Date(2025, 8, 15, 10, 30, 0, 0, berlin), nil},
+		{"2025-08-15T10:30:00", time.UTC, time.Date(2025, 8, 15, 10, 30, 0, 0, time.UTC), nil},
+		{"August 15, 2025", time.UTC, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), nil},
+		{"15 Aug 2025", time.UTC, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), nil},
+		{
+			time.Date(2025, 8, 15, 0, 0, 0, 0, toml.LocalDate), berlin,
+			time.Date(2025, 8, 15, 0, 0, 0, 0, berlin), nil,
+		},
+		{
+			time.Date(2025, 8, 15, 10, 30, 0, 0, toml.LocalDatetime), berlin,
+			time.Date(2025, 8, 15, 10, 30, 0, 0, berlin), nil,
+		},
+		{
+			time.Date(2025, 8, 15, 10, 30, 0, 0, plus2), berlin,
+			time.Date(2025, 8, 15, 10, 30, 0, 0, plus2), nil,
+		},
+		{
+			time.Date(0, 1, 1, 10, 30, 0, 0, toml.LocalTime), time.UTC,
+			time.Time{}, fmt.Errorf("10:30:00 is a time without a date"),
+		},
+		{
+			"tomorrow", time.UTC, time.Time{},
+			fmt.Errorf(`"tomorrow" is not a date. use a format like 2006-01-02 or 2006-01-02T15:04:05Z07:00`),
+		},
+		{int64(2025), time.UTC, time.Time{}, fmt.Errorf("2025 is not a date")},
+		{yamlDate(t, "2025-08-15"), berlin, time.Date(2025, 8, 15, 0, 0, 0, 0, berlin), nil},
+		{yamlDate(t, "2025-08-15 10:30:00"), berlin, time.Date(2025, 8, 15, 10, 30, 0, 0, berlin), nil},
+		{
+			yamlDate(t, "2025-08-15T10:30:00+02:00"), berlin,
+			time.Date(2025, 8, 15, 10, 30, 0, 0, plus2), nil,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			date, err := parseDate(tt.value, tt.loc)
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%v. got=%v", tt.err, err)
+			}
+			if !date.Equal(tt.expected) || date.Location().String() != tt.expected.Location().String() {
+				t.Errorf("wrong date. expected=%v. got=%v", tt.expected, date)
+			}
+		})
+	}
+}
+
+// yamlDate returns the date in YAML front matter
+func yamlDate(t *testing.T, date string) any {
+	doc := mdToHTML(t, "---\ndate: "+date+"\n---\n")
+	return doc.Metadata["date"]
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2025, 8, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"", "15-08-2025"},
+		{"January 2, 2006", "August 15, 2025"},
+		{time.DateTime, "2025-08-15 10:30:00"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if s := formatDate(date, tt.layout); s != tt.expected {
+				t.Errorf("wrong date. expected=%q. got=%q", tt.expected, s)
+			}
+		})
+	}
+}
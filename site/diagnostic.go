@@ -0,0 +1,134 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/markdown"
+	"github.com/Hassan-Ibrahim-1/go-ssg/toml"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found while building or checking a site.
+type Diagnostic struct {
+	// File is the path of the file relative to the site's root.
+	// empty if the diagnostic doesn't belong to a single file
+	File string `json:"file"`
+	// Line and Column start at 1. they are 0 if the position is unknown
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String formats the diagnostic like a compiler would.
+// e.g. content/post.md:3:1: error: message
+func (d Diagnostic) String() string {
+	var pos strings.Builder
+	pos.WriteString(d.File)
+	if d.File != "" && d.Line > 0 {
+		fmt.Fprintf(&pos, ":%d", d.Line)
+		if d.Column > 0 {
+			fmt.Fprintf(&pos, ":%d", d.Column)
+		}
+	}
+	if pos.Len() > 0 {
+		pos.WriteString(": ")
+	}
+	return fmt.Sprintf("%s%s: %s", pos.String(), d.Severity, d.Message)
+}
+
+func (d Diagnostic) Error() string {
+	return d.String()
+}
+
+// Diagnostics is returned as an error by Build when at least one of them
+// is an error.
+type Diagnostics []Diagnostic
+
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (ds Diagnostics) Unwrap() []error {
+	errs := make([]error, len(ds))
+	for i, d := range ds {
+		errs[i] = d
+	}
+	return errs
+}
+
+func (ds Diagnostics) ErrorCount() int {
+	count := 0
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+func (ds Diagnostics) HasErrors() bool {
+	return ds.ErrorCount() > 0
+}
+
+// newDiagnostic creates an error diagnostic for file.
+// the position is taken from err if it has one
+func newDiagnostic(file string, err error) Diagnostic {
+	d := Diagnostic{
+		File:     file,
+		Severity: SeverityError,
+		Message:  err.Error(),
+	}
+
+	var metadataErr *markdown.MetadataError
+	var tomlErr *toml.ParseError
+	var decodeErr *toml.DecodeError
+	var layoutErr *LayoutError
+
+	switch {
+	case errors.As(err, &metadataErr):
+		d.Line = metadataErr.Line
+		d.Column = metadataErr.Column
+		d.Message = "invalid metadata: " + metadataErr.Msg
+	case errors.As(err, &tomlErr):
+		d.Line = tomlErr.Line
+		d.Column = tomlErr.Column
+		d.Message = tomlErr.Msg
+	case errors.As(err, &decodeErr):
+		d.Line = decodeErr.Line
+		d.Column = decodeErr.Column
+		d.Message = decodeErr.Path + ": " + decodeErr.Msg
+	case errors.As(err, &layoutErr):
+		d.File = layoutErr.File
+		d.Line = layoutErr.Line
+		d.Message = "invalid layout: " + layoutErr.Msg
+	}
+
+	return d
+}
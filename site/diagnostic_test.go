@@ -0,0 +1,87 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDiagnosticString(t *testing.T) {
+	tests := []struct {
+		diagnostic Diagnostic
+		expected   string
+	}{
+		{
+			Diagnostic{"content/a.md", 3, 7, SeverityError, "bad"},
+			"content/a.md:3:7: error: bad",
+		},
+		{
+			Diagnostic{"content/a.md", 3, 0, SeverityWarning, "bad"},
+			"content/a.md:3: warning: bad",
+		},
+		{
+			Diagnostic{"content/a.md", 0, 0, SeverityError, "bad"},
+			"content/a.md: error: bad",
+		},
+		{
+			Diagnostic{"", 0, 0, SeverityError, "bad"},
+			"error: bad",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if s := tt.diagnostic.String(); s != tt.expected {
+				t.Errorf("expected=%q. got=%q", tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestBuildFromEntriesDiagnostics(t *testing.T) {
+	entries := []Entry{
+		defaultSsgTomlEntry(),
+		defaultThemeDirEntry(),
+		&testEntry{
+			name: "content",
+			typ:  DirectoryEntry,
+			children: []Entry{
+				&testEntry{
+					name:    "content/a.md",
+					typ:     FileEntry,
+					content: "+++\ntitle = a\n\ndate: 01-01-2000\n+++\n",
+				},
+				&testEntry{
+					name:    "content/b.md",
+					typ:     FileEntry,
+					content: "+++\ntitle = b\ndate = 01-01-2000\n+++\n",
+				},
+				&testEntry{
+					name:    "content/c.md",
+					typ:     FileEntry,
+					content: "+++\ndate = 01-01-2000\n+++\n",
+				},
+			},
+		},
+	}
+
+	_, err := BuildFromEntries(entries, BuildOptions{})
+
+	var diagnostics Diagnostics
+	if !errors.As(err, &diagnostics) {
+		t.Fatalf("expected Diagnostics. got=%v", err)
+	}
+
+	expected := Diagnostics{
+		{"content/a.md", 4, 0, SeverityError, "invalid metadata: Not a valid key value pair \"date: 01-01-2000\", expected key = value"},
+		{"content/c.md", 0, 0, SeverityError, "blog title not found"},
+	}
+
+	if diagnostics.Error() != expected.Error() {
+		t.Errorf(
+			"wrong diagnostics.\nexpected=\n%s\ngot=\n%s",
+			expected,
+			diagnostics,
+		)
+	}
+}
@@ -0,0 +1,454 @@
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// FeedConfig is the [feed] table in ssg.toml
+type FeedConfig struct {
+	// Enabled turns off feeds if it's false
+	Enabled bool `toml:"enabled" default:"true"`
+	// Limit is the maximum number of pages in a feed. 0 includes every page
+	Limit int `toml:"limit" default:"20"`
+	// FullContent includes the content of pages instead of their summary
+	FullContent bool `toml:"full_content"`
+	// Sections are the top level directories that pages are taken from.
+	// defaults to content
+	Sections []string `toml:"sections,omitempty"`
+	// Drafts includes drafts in feeds when they are built.
+	// they are left out by default
+	Drafts bool `toml:"drafts"`
+}
+
+const (
+	rssFeedName  = "feed.xml"
+	atomFeedName = "atom.xml"
+	jsonFeedName = "feed.json"
+)
+
+// FeedLink is a feed of the site in .Site.Feeds
+type FeedLink struct {
+	// Type is the media type of the feed. e.g. application/rss+xml
+	Type string
+	URL  string
+}
+
+// feedLinks returns the feeds that are generated for the site.
+// feeds need absolute links so they're only generated if base_url is set
+func feedLinks(site *SiteContext) []FeedLink {
+	if !site.Config.Feed.Enabled || site.BaseURL == "" {
+		return nil
+	}
+	return []FeedLink{
+		{Type: "application/rss+xml", URL: absURL(site.BaseURL, rssFeedName)},
+		{Type: "application/atom+xml", URL: absURL(site.BaseURL, atomFeedName)},
+		{Type: "application/feed+json", URL: absURL(site.BaseURL, jsonFeedName)},
+	}
+}
+
+// feed is a list of pages that feeds are generated from
+type feed struct {
+	title string
+	// dir is the directory the feed is generated in. e.g. tags/go.
+	// empty for the feeds of the site
+	dir   string
+	pages []*PageContext
+}
+
+// siteFeed has the pages in the feeds of the site. they're in the
+// same order as the pages listed by index.html, newest first
+func siteFeed(site *SiteContext) feed {
+	sections := site.Config.Feed.Sections
+	if len(sections) == 0 {
+		sections = []string{"content"}
+	}
+
+	var pages []*PageContext
+	for _, page := range site.Pages {
+		if slices.Contains(sections, page.Section) {
+			pages = append(pages, page)
+		}
+	}
+	return feed{title: site.Title, pages: feedPages(site.Config.Feed, pages)}
+}
+
+// feedPages leaves out drafts and the pages over the limit in config
+func feedPages(config FeedConfig, pages []*PageContext) []*PageContext {
+	var included []*PageContext
+	for _, page := range pages {
+		if page.Draft && !config.Drafts {
+			continue
+		}
+		included = append(included, page)
+		if config.Limit > 0 && len(included) == config.Limit {
+			break
+		}
+	}
+	return included
+}
+
+// home is the url of the page that lists the feed's pages
+func (f feed) home(site *SiteContext) string {
+	if f.dir == "" {
+		return absURL(site.BaseURL, "/")
+	}
+	return absURL(site.BaseURL, f.dir+"/")
+}
+
+// url is the url of the feed file called name
+func (f feed) url(site *SiteContext, name string) string {
+	return absURL(site.BaseURL, path.Join(f.dir, name))
+}
+
+// feedUpdated is the date the newest page was changed.
+// the zero time if there are no pages
+func feedUpdated(pages []*PageContext) time.Time {
+	var updated time.Time
+	for _, page := range pages {
+		if page.Lastmod.After(updated) {
+			updated = page.Lastmod
+		}
+	}
+	return updated
+}
+
+func feedContent(site *SiteContext, page *PageContext, fullContent bool) string {
+	if fullContent {
+		return absoluteLinks(site.BaseURL, page, string(page.Content))
+	}
+	return absoluteLinks(site.BaseURL, page, string(page.Summary))
+}
+
+// absoluteLinks makes the links in the html of page absolute since feed
+// readers don't know where it came from. paths that start with / are
+// relative to baseURL and other paths to the page's permalink
+func absoluteLinks(baseURL string, page *PageContext, content string) string {
+	permalink, err := url.Parse(page.Permalink)
+	if err != nil {
+		return content
+	}
+
+	var out strings.Builder
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return out.String()
+		}
+		raw := string(z.Raw())
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.WriteString(raw)
+			continue
+		}
+
+		token := z.Token()
+		changed := false
+		for i, attr := range token.Attr {
+			if attr.Key != "href" && attr.Key != "src" {
+				continue
+			}
+			if link, ok := absoluteLink(baseURL, permalink, attr.Val); ok {
+				token.Attr[i].Val = link
+				changed = true
+			}
+		}
+		if changed {
+			out.WriteString(token.String())
+		} else {
+			out.WriteString(raw)
+		}
+	}
+}
+
+// absoluteLink returns false if link is already absolute
+func absoluteLink(baseURL string, permalink *url.URL, link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || link == "" || u.Scheme != "" || u.Host != "" {
+		return "", false
+	}
+	if strings.HasPrefix(link, "/") {
+		return absURL(baseURL, link), true
+	}
+	return permalink.ResolveReference(u).String(), true
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func generateRSSFeed(site *SiteContext, f feed) ([]byte, error) {
+	rss := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       f.title,
+			Link:        f.home(site),
+			Description: "Recent posts on " + f.title,
+			AtomLink: atomLink{
+				Href: f.url(site, rssFeedName),
+				Rel:  "self",
+				Type: "application/rss+xml",
+			},
+			Items: make([]rssItem, len(f.pages)),
+		},
+	}
+	if updated := feedUpdated(f.pages); !updated.IsZero() {
+		rss.Channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+
+	for i, page := range f.pages {
+		rss.Channel.Items[i] = rssItem{
+			Title:       page.Title,
+			Link:        page.Permalink,
+			GUID:        rssGUID{IsPermaLink: true, Value: page.Permalink},
+			PubDate:     page.Date.Format(time.RFC1123Z),
+			Description: feedContent(site, page, site.Config.Feed.FullContent),
+			Categories:  page.Tags,
+		}
+	}
+
+	return marshalFeed(rss)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func generateAtomFeed(site *SiteContext, f feed) ([]byte, error) {
+	updated := feedUpdated(f.pages)
+	if updated.IsZero() {
+		updated = site.BuildTime
+	}
+
+	atom := atomFeed{
+		Title:   f.title,
+		ID:      f.home(site),
+		Updated: updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.home(site)},
+			{
+				Href: f.url(site, atomFeedName),
+				Rel:  "self",
+				Type: "application/atom+xml",
+			},
+		},
+		Author:  atomAuthor{Name: site.Author},
+		Entries: make([]atomEntry, len(f.pages)),
+	}
+
+	for i, page := range f.pages {
+		entry := atomEntry{
+			Title:     page.Title,
+			ID:        page.Permalink,
+			Link:      atomLink{Href: page.Permalink},
+			Published: page.Date.Format(time.RFC3339),
+			Updated:   page.Lastmod.Format(time.RFC3339),
+		}
+		if author := page.Author(); author != "" {
+			entry.Author = &atomAuthor{Name: author}
+		}
+		for _, tag := range page.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+
+		text := &atomText{
+			Type: "html",
+			Body: feedContent(site, page, site.Config.Feed.FullContent),
+		}
+		if site.Config.Feed.FullContent {
+			entry.Content = text
+		} else {
+			entry.Summary = text
+		}
+
+		atom.Entries[i] = entry
+	}
+
+	return marshalFeed(atom)
+}
+
+// jsonFeed follows https://www.jsonfeed.org/version/1.1/
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Authors     []jsonAuthor   `json:"authors,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	Summary       string       `json:"summary,omitempty"`
+	DatePublished string       `json:"date_published"`
+	DateModified  string       `json:"date_modified"`
+	Tags          []string     `json:"tags,omitempty"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+}
+
+// generateJSONFeed always has the full content of pages. summaries are
+// plain text
+func generateJSONFeed(site *SiteContext, f feed) ([]byte, error) {
+	jf := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.title,
+		HomePageURL: f.home(site),
+		FeedURL:     f.url(site, jsonFeedName),
+		Description: "Recent posts on " + f.title,
+		Items:       make([]jsonFeedItem, len(f.pages)),
+	}
+	if site.Author != "" {
+		jf.Authors = []jsonAuthor{{Name: site.Author}}
+	}
+
+	for i, page := range f.pages {
+		item := jsonFeedItem{
+			ID:            page.Permalink,
+			URL:           page.Permalink,
+			Title:         page.Title,
+			ContentHTML:   absoluteLinks(site.BaseURL, page, string(page.Content)),
+			Summary:       strings.TrimSpace(plainText(page.Summary)),
+			DatePublished: page.Date.Format(time.RFC3339),
+			DateModified:  page.Lastmod.Format(time.RFC3339),
+			Tags:          page.Tags,
+		}
+		if author := page.Author(); author != "" {
+			item.Authors = []jsonAuthor{{Name: author}}
+		}
+		jf.Items[i] = item
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(jf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalFeed(feed any) ([]byte, error) {
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// generateFeeds returns the nodes of every feed in site.Feeds.
+// feeds that would replace a file of the site aren't generated
+func generateFeeds(site *SiteContext, nodes []Node) ([]Node, error) {
+	if len(site.Feeds) == 0 {
+		return nil, nil
+	}
+
+	f := siteFeed(site)
+	generators := []struct {
+		name     string
+		generate func(*SiteContext, feed) ([]byte, error)
+	}{
+		{rssFeedName, generateRSSFeed},
+		{atomFeedName, generateAtomFeed},
+		{jsonFeedName, generateJSONFeed},
+	}
+
+	var feeds []Node
+	for _, g := range generators {
+		if findNode(nodes, g.name) != nil {
+			continue
+		}
+		content, err := g.generate(site, f)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, Node{
+			Name:    g.name,
+			Type:    FileNode,
+			Content: content,
+		})
+	}
+	return feeds, nil
+}
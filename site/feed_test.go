@@ -0,0 +1,257 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// feedTestFiles are the pages of the feed tests
+var feedTestFiles = map[string]string{
+	"content/a.md": "+++\ntitle = \"A & B\"\ndate = 2024-01-01\ntags = [\"go\"]\n+++\nfirst <3\n\nsecond",
+	"content/b.md": "+++\ntitle = \"b\"\ndate = 2024-02-01T10:00:00Z\nlastmod = 2024-03-01\nauthor = \"someone\"\n+++\nhello",
+	"content/c.md": "+++\ntitle = \"c\"\ndate = 2024-04-01\ndraft = true\n+++\ndraft",
+	"about.md":     "+++\ntitle = \"about\"\ndate = 2024-05-01\n+++\nabout",
+}
+
+func TestRSSFeed(t *testing.T) {
+	s, err := BuildFromEntries(siteEntries(baseURLConfig, feedTestFiles), BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := findNode(s.Nodes, "feed.xml")
+	if feed == nil {
+		t.Fatal("feed.xml wasn't generated")
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>test blog</title>
+    <link>https://example.com/</link>
+    <description>Recent posts on test blog</description>
+    <atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"></atom:link>
+    <lastBuildDate>Fri, 01 Mar 2024 00:00:00 +0000</lastBuildDate>
+    <item>
+      <title>b</title>
+      <link>https://example.com/content/b/</link>
+      <guid isPermaLink="true">https://example.com/content/b/</guid>
+      <pubDate>Thu, 01 Feb 2024 10:00:00 +0000</pubDate>
+      <description>&lt;p&gt;hello&lt;/p&gt;</description>
+    </item>
+    <item>
+      <title>A &amp; B</title>
+      <link>https://example.com/content/a/</link>
+      <guid isPermaLink="true">https://example.com/content/a/</guid>
+      <pubDate>Mon, 01 Jan 2024 00:00:00 +0000</pubDate>
+      <description>&lt;p&gt;first &amp;lt;3&lt;/p&gt;</description>
+      <category>go</category>
+    </item>
+  </channel>
+</rss>
+`
+	if string(feed.Content) != expected {
+		t.Errorf("wrong feed.\nexpected=\n%s\ngot=\n%s", expected, feed.Content)
+	}
+}
+
+func TestAtomFeed(t *testing.T) {
+	s, err := BuildFromEntries(
+		siteEntries(baseURLConfig+"[feed]\nfull_content = true\n", feedTestFiles),
+		BuildOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := findNode(s.Nodes, "atom.xml")
+	if feed == nil {
+		t.Fatal("atom.xml wasn't generated")
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>test blog</title>
+  <id>https://example.com/</id>
+  <updated>2024-03-01T00:00:00Z</updated>
+  <link href="https://example.com/"></link>
+  <link href="https://example.com/atom.xml" rel="self" type="application/atom+xml"></link>
+  <author>
+    <name>test author</name>
+  </author>
+  <entry>
+    <title>b</title>
+    <id>https://example.com/content/b/</id>
+    <link href="https://example.com/content/b/"></link>
+    <published>2024-02-01T10:00:00Z</published>
+    <updated>2024-03-01T00:00:00Z</updated>
+    <author>
+      <name>someone</name>
+    </author>
+    <content type="html">&lt;p&gt;hello&lt;/p&gt;&#xA;</content>
+  </entry>
+  <entry>
+    <title>A &amp; B</title>
+    <id>https://example.com/content/a/</id>
+    <link href="https://example.com/content/a/"></link>
+    <published>2024-01-01T00:00:00Z</published>
+    <updated>2024-01-01T00:00:00Z</updated>
+    <category term="go"></category>
+    <content type="html">&lt;p&gt;first &amp;lt;3&lt;/p&gt;&#xA;&#xA;&lt;p&gt;second&lt;/p&gt;&#xA;</content>
+  </entry>
+</feed>
+`
+	if string(feed.Content) != expected {
+		t.Errorf("wrong feed.\nexpected=\n%s\ngot=\n%s", expected, feed.Content)
+	}
+}
+
+func TestFeedConfig(t *testing.T) {
+	tests := []struct {
+		feedConfig  string
+		buildDrafts bool
+		extra       []Entry
+		// links that have to be in feed.xml. nil if it shouldn't exist
+		links []string
+	}{
+		{"", false, nil, []string{"/content/b/", "/content/a/"}},
+		{"", true, nil, []string{"/content/b/", "/content/a/"}},
+		{"[feed]\ndrafts = true\n", true, nil, []string{"/content/c/", "/content/b/", "/content/a/"}},
+		{"[feed]\nlimit = 1\n", false, nil, []string{"/content/b/"}},
+		{"[feed]\nsections = [\"\"]\n", false, nil, []string{"/about/"}},
+		{"[feed]\nenabled = false\n", false, nil, nil},
+		{
+			"", false,
+			[]Entry{&testEntry{name: "feed.xml", typ: FileEntry, content: "mine"}},
+			[]string{"mine"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			s, err := BuildFromEntries(
+				append(siteEntries(baseURLConfig+tt.feedConfig, feedTestFiles), tt.extra...),
+				BuildOptions{BuildDrafts: tt.buildDrafts},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			feed := findNode(s.Nodes, "feed.xml")
+			if tt.links == nil {
+				if feed != nil {
+					t.Errorf("feed.xml shouldn't be generated")
+				}
+				return
+			}
+			if feed == nil {
+				t.Fatal("feed.xml wasn't generated")
+			}
+
+			count := bytes.Count(feed.Content, []byte("<item>"))
+			if tt.links[0] != "mine" && count != len(tt.links) {
+				t.Errorf("expected %d items. got %d\n%s", len(tt.links), count, feed.Content)
+			}
+			last := -1
+			for _, link := range tt.links {
+				i := bytes.Index(feed.Content, []byte(link+"<"))
+				if i == -1 {
+					i = bytes.Index(feed.Content, []byte(link))
+				}
+				if i <= last {
+					t.Errorf("%s is missing or in the wrong order\n%s", link, feed.Content)
+				}
+				last = i
+			}
+		})
+	}
+}
+
+func TestFeedLinks(t *testing.T) {
+	s, err := BuildFromEntries(siteEntries(baseURLConfig, feedTestFiles), BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := findNode(s.Nodes, "content/a/index.html")
+	link := `<link rel="alternate" type="application/rss&#43;xml" title="test blog" href="https://example.com/feed.xml" />`
+	if !bytes.Contains(page.Content, []byte(link)) {
+		t.Errorf("expected the page to link to the feed. got=\n%s", page.Content)
+	}
+
+	s, err = BuildFromEntries(
+		siteEntries("", map[string]string{
+			"content/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\n+++\n",
+		}),
+		BuildOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findNode(s.Nodes, "feed.xml") != nil ||
+		findNode(s.Nodes, "atom.xml") != nil ||
+		findNode(s.Nodes, "feed.json") != nil {
+		t.Errorf("feeds shouldn't be generated without a base_url")
+	}
+	page = findNode(s.Nodes, "content/a/index.html")
+	if bytes.Contains(page.Content, []byte(`rel="alternate"`)) {
+		t.Errorf("the page shouldn't link to feeds without a base_url")
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	s, err := BuildFromEntries(siteEntries(baseURLConfig, feedTestFiles), BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := findNode(s.Nodes, "feed.json")
+	if feed == nil {
+		t.Fatal("feed.json wasn't generated")
+	}
+
+	expected := `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "test blog",
+  "home_page_url": "https://example.com/",
+  "feed_url": "https://example.com/feed.json",
+  "description": "Recent posts on test blog",
+  "authors": [
+    {
+      "name": "test author"
+    }
+  ],
+  "items": [
+    {
+      "id": "https://example.com/content/b/",
+      "url": "https://example.com/content/b/",
+      "title": "b",
+      "content_html": "<p>hello</p>\n",
+      "summary": "hello",
+      "date_published": "2024-02-01T10:00:00Z",
+      "date_modified": "2024-03-01T00:00:00Z",
+      "authors": [
+        {
+          "name": "someone"
+        }
+      ]
+    },
+    {
+      "id": "https://example.com/content/a/",
+      "url": "https://example.com/content/a/",
+      "title": "A & B",
+      "content_html": "<p>first &lt;3</p>\n\n<p>second</p>\n",
+      "summary": "first <3",
+      "date_published": "2024-01-01T00:00:00Z",
+      "date_modified": "2024-01-01T00:00:00Z",
+      "tags": [
+        "go"
+      ]
+    }
+  ]
+}
+`
+	if string(feed.Content) != expected {
+		t.Errorf("wrong feed.\nexpected=\n%s\ngot=\n%s", expected, feed.Content)
+	}
+}
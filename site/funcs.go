@@ -0,0 +1,339 @@
+package site
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"html/template"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/markdown"
+	"golang.org/x/net/html"
+)
+
+// templateFuncs are the functions that every layout can use
+func templateFuncs(config SiteConfig) template.FuncMap {
+	return template.FuncMap{
+		"dateFormat": dateFormat,
+		"absURL": func(path string) string {
+			return absURL(config.BaseURL, path)
+		},
+		"relURL": func(path string) string {
+			return relURL(config.BaseURL, path)
+		},
+		"markdownify": markdownify,
+		"truncate":    truncate,
+		"where":       where,
+		"sortBy":      sortBy,
+		"first":       first,
+		"groupByYear": groupByYear,
+		"readingTime": readingTime,
+	}
+}
+
+// dateFormat formats t with layout. the layout is DateLayout if it's empty
+func dateFormat(layout string, t time.Time) string {
+	return formatDate(t, layout)
+}
+
+func isAbsURL(path string) bool {
+	if strings.HasPrefix(path, "//") {
+		return true
+	}
+	u, err := url.Parse(path)
+	return err == nil && u.IsAbs()
+}
+
+// basePath is the path of baseURL without a trailing slash. e.g. /blog
+// for https://example.com/blog/. empty if the site is at the root of its
+// domain
+func basePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// BasePath is the path that links of the site start with. see basePath
+func (config SiteConfig) BasePath() string {
+	return basePath(config.BaseURL)
+}
+
+// relURL makes path relative to the root of the site. the path of baseURL
+// is added so that links work when the site isn't at the root of its domain
+func relURL(baseURL, path string) string {
+	if isAbsURL(path) {
+		return path
+	}
+	return basePath(baseURL) + "/" + strings.TrimPrefix(path, "/")
+}
+
+// absURL joins baseURL and path. it's the same as relURL if baseURL is empty
+func absURL(baseURL, path string) string {
+	if isAbsURL(path) || baseURL == "" {
+		return relURL(baseURL, path)
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// markdownify converts s to html. text that is a single paragraph isn't
+// wrapped in a <p> so that it can be used inline
+func markdownify(s string) template.HTML {
+	out := bytes.TrimSpace(markdown.Render([]byte(s)))
+	if bytes.HasPrefix(out, []byte("<p>")) &&
+		bytes.HasSuffix(out, []byte("</p>")) &&
+		bytes.Count(out, []byte("<p>")) == 1 {
+		out = out[len("<p>") : len(out)-len("</p>")]
+	}
+	return template.HTML(out)
+}
+
+// plainText returns the text of v. html is stripped of its tags
+func plainText(v any) string {
+	h, ok := v.(template.HTML)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(string(h)))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return text.String()
+		case html.TextToken:
+			text.Write(tokenizer.Text())
+		}
+	}
+}
+
+// truncate cuts text down to length characters and adds an ellipsis
+// if it's longer than that
+func truncate(length int, text any) string {
+	s := plainText(text)
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:length])) + "…"
+}
+
+// readingTime returns the minutes it takes to read content. at least 1
+func readingTime(content any) int {
+	const wordsPerMinute = 200
+	words := len(strings.Fields(plainText(content)))
+	return max(1, (words+wordsPerMinute-1)/wordsPerMinute)
+}
+
+// fieldValue returns the value of key in item. key can be a field, a method
+// without arguments or a map key. keys are separated by dots
+// e.g. Params.author
+func fieldValue(item reflect.Value, key string) (reflect.Value, bool) {
+	for _, name := range strings.Split(key, ".") {
+		if method := item.MethodByName(name); method.IsValid() &&
+			method.Type().NumIn() == 0 && method.Type().NumOut() == 1 {
+			item = method.Call(nil)[0]
+			continue
+		}
+
+		for item.Kind() == reflect.Pointer || item.Kind() == reflect.Interface {
+			if item.IsNil() {
+				return reflect.Value{}, false
+			}
+			item = item.Elem()
+		}
+
+		switch item.Kind() {
+		case reflect.Struct:
+			item = item.FieldByName(name)
+		case reflect.Map:
+			if item.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			item = item.MapIndex(reflect.ValueOf(name).Convert(item.Type().Key()))
+		default:
+			return reflect.Value{}, false
+		}
+		if !item.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+
+	for item.Kind() == reflect.Interface && !item.IsNil() {
+		item = item.Elem()
+	}
+	return item, true
+}
+
+func sliceValue(items any) (reflect.Value, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("expected a list. got %T", items)
+	}
+	return v, nil
+}
+
+// valuesEqual compares values by their text so that numbers of different
+// types can be compared
+func valuesEqual(a reflect.Value, b any) bool {
+	if a.CanInterface() && reflect.DeepEqual(a.Interface(), b) {
+		return true
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// where returns the items whose key is value. if key is a list the items
+// that have value in it are returned
+func where(items any, key string, value any) (any, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, v.Len())
+	for i := range v.Len() {
+		field, ok := fieldValue(v.Index(i), key)
+		if !ok {
+			continue
+		}
+
+		matched := false
+		if field.Kind() == reflect.Slice || field.Kind() == reflect.Array {
+			for j := range field.Len() {
+				if valuesEqual(field.Index(j), value) {
+					matched = true
+					break
+				}
+			}
+		} else {
+			matched = valuesEqual(field, value)
+		}
+
+		if matched {
+			matches = reflect.Append(matches, v.Index(i))
+		}
+	}
+	return matches.Interface(), nil
+}
+
+// compareValues orders times, numbers and strings. values of other types
+// are compared by their text
+func compareValues(a, b reflect.Value) int {
+	if a.Kind() != b.Kind() {
+		return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+
+	if a.CanInterface() && b.CanInterface() {
+		at, aok := a.Interface().(time.Time)
+		bt, bok := b.Interface().(time.Time)
+		if aok && bok {
+			return at.Compare(bt)
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return cmp.Compare(fmt.Sprint(a.Bool()), fmt.Sprint(b.Bool()))
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// sortBy returns a copy of items sorted by key. order is asc or desc and
+// defaults to asc. items without the key are last
+func sortBy(items any, key string, order ...string) (any, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := false
+	if len(order) > 0 {
+		switch order[0] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return nil, fmt.Errorf("invalid sort order %s. expected asc or desc", order[0])
+		}
+	}
+
+	sorted := make([]reflect.Value, v.Len())
+	for i := range sorted {
+		sorted[i] = v.Index(i)
+	}
+	slices.SortStableFunc(sorted, func(a, b reflect.Value) int {
+		af, aok := fieldValue(a, key)
+		bf, bok := fieldValue(b, key)
+		switch {
+		case !aok && !bok:
+			return 0
+		case !aok:
+			return 1
+		case !bok:
+			return -1
+		}
+		c := compareValues(af, bf)
+		if desc {
+			return -c
+		}
+		return c
+	})
+
+	out := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, len(sorted))
+	out = reflect.Append(out, sorted...)
+	return out.Interface(), nil
+}
+
+// first returns the first n items
+func first(n int, items any) (any, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("first needs a positive number. got %d", n)
+	}
+	return v.Slice(0, min(n, v.Len())).Interface(), nil
+}
+
+// PageGroup is a list of pages returned by groupByYear
+type PageGroup struct {
+	Year  int
+	Pages []*PageContext
+}
+
+// groupByYear groups pages by the year they were published in.
+// the newest year is first and pages stay in the same order
+func groupByYear(pages []*PageContext) []PageGroup {
+	var groups []PageGroup
+	for _, page := range pages {
+		year := page.Date.Year()
+		i := slices.IndexFunc(groups, func(g PageGroup) bool {
+			return g.Year == year
+		})
+		if i == -1 {
+			groups = append(groups, PageGroup{Year: year})
+			i = len(groups) - 1
+		}
+		groups[i].Pages = append(groups[i].Pages, page)
+	}
+
+	slices.SortStableFunc(groups, func(a, b PageGroup) int {
+		return cmp.Compare(b.Year, a.Year)
+	})
+	return groups
+}
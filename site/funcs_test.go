@@ -0,0 +1,297 @@
+package site
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"html/template"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestURLFuncs(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		path    string
+		rel     string
+		abs     string
+	}{
+		{"", "posts/a.html", "/posts/a.html", "/posts/a.html"},
+		{"", "/posts/a.html", "/posts/a.html", "/posts/a.html"},
+		{"https://example.com", "/a.html", "/a.html", "https://example.com/a.html"},
+		{"https://example.com/", "a.html", "/a.html", "https://example.com/a.html"},
+		{"https://example.com/blog", "/", "/blog/", "https://example.com/blog/"},
+		{"https://example.com/blog/", "posts/a/", "/blog/posts/a/", "https://example.com/blog/posts/a/"},
+		{"https://example.com", "https://go.dev", "https://go.dev", "https://go.dev"},
+		{"https://example.com", "//cdn.example.com/a.js", "//cdn.example.com/a.js", "//cdn.example.com/a.js"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if rel := relURL(tt.baseURL, tt.path); rel != tt.rel {
+				t.Errorf("wrong relURL. expected=%q. got=%q", tt.rel, rel)
+			}
+			if abs := absURL(tt.baseURL, tt.path); abs != tt.abs {
+				t.Errorf("wrong absURL. expected=%q. got=%q", tt.abs, abs)
+			}
+		})
+	}
+}
+
+func TestBaseURLPath(t *testing.T) {
+	config := `base_url = "https://example.com/blog"
+paginate = 1
+
+[[menu.main]]
+page = "about.md"
+`
+	entries := siteEntries(config, map[string]string{
+		"about.md":     "+++\ntitle = \"About\"\ndate = 2024-01-01\n+++\n",
+		"content/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\n+++\n",
+		"content/b.md": "+++\ntitle = \"b\"\ndate = 2024-02-01\ntags = [\"go\"]\n+++\n" +
+			"[a](/content/a/) [up](../a/) [go](https://go.dev) ![img](../img.png)",
+		"content/img.png":       "png",
+		"content/notes/n.md":    "+++\ntitle = \"n\"\ndate = 2023-01-01\n+++\n",
+		"content/notes/go/g.md": "+++\ntitle = \"g\"\ndate = 2023-01-01\n+++\n",
+	})
+
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		contains []string
+	}{
+		{
+			"index.html",
+			[]string{
+				`href="/blog/themes/dark.css"`,
+				`<a href="/blog/">test blog</a>`,
+				`<a class="menu-item" href="/blog/about/">About</a>`,
+				`<a class="blog-item" href="/blog/content/b/">`,
+			},
+		},
+		{"tags/index.html", []string{`<a class="term-item" href="/blog/tags/go/">`}},
+		{"tags/go/index.html", []string{`<a class="blog-item" href="/blog/content/b/">`}},
+		{"page/2/index.html", []string{`<a class="pagination-prev" href="/blog/">`}},
+		{
+			"content/notes/index.html",
+			[]string{
+				`<a class="section-item" href="/blog/content/notes/go/">`,
+				`<a class="blog-item" href="/blog/content/notes/n/">`,
+			},
+		},
+		{
+			"feed.xml",
+			[]string{
+				`&lt;a href=&#34;https://example.com/blog/content/a/&#34; rel=&#34;nofollow&#34;&gt;a&lt;/a&gt;`,
+				`&lt;a href=&#34;https://example.com/blog/content/a/&#34; rel=&#34;nofollow&#34;&gt;up&lt;/a&gt;`,
+				`&lt;a href=&#34;https://go.dev&#34; rel=&#34;nofollow&#34;&gt;go&lt;/a&gt;`,
+				`src=&#34;https://example.com/blog/content/img.png&#34;`,
+			},
+		},
+		{
+			jsonFeedName,
+			[]string{
+				`<a href=\"https://example.com/blog/content/a/\" rel=\"nofollow\">a</a>`,
+				`<a href=\"https://example.com/blog/content/a/\" rel=\"nofollow\">up</a>`,
+				`<a href=\"https://go.dev\" rel=\"nofollow\">go</a>`,
+				`src=\"https://example.com/blog/content/img.png\"`,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			for _, str := range tt.contains {
+				if !bytes.Contains(node.Content, []byte(str)) {
+					t.Errorf("expected %s to contain %s. got=\n%s", tt.name, str, node.Content)
+				}
+			}
+		})
+	}
+
+	// links with the path of base_url aren't broken
+	if diagnostics := CheckEntries(entries, BuildOptions{}); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics. got %v", diagnostics)
+	}
+}
+
+func TestTextFuncs(t *testing.T) {
+	if s := markdownify("some *text*"); s != "some <em>text</em>" {
+		t.Errorf("wrong markdownify. got=%q", s)
+	}
+	if s := markdownify("a\n\nb"); s != "<p>a</p>\n\n<p>b</p>" {
+		t.Errorf("wrong markdownify. got=%q", s)
+	}
+
+	truncateTests := []struct {
+		length   int
+		text     any
+		expected string
+	}{
+		{5, "hello", "hello"},
+		{5, "hello world", "hello…"},
+		{6, "hello world", "hello…"},
+		{3, "héllo", "hél…"},
+		{7, template.HTML("<p>hello <em>world</em></p>"), "hello w…"},
+	}
+	for i, tt := range truncateTests {
+		if s := truncate(tt.length, tt.text); s != tt.expected {
+			t.Errorf("test_%d: wrong truncate. expected=%q. got=%q", i, tt.expected, s)
+		}
+	}
+
+	readingTimeTests := []struct {
+		content  any
+		expected int
+	}{
+		{"", 1},
+		{"a few words", 1},
+		{template.HTML("<p>" + repeatWords(450) + "</p>"), 3},
+	}
+	for i, tt := range readingTimeTests {
+		if m := readingTime(tt.content); m != tt.expected {
+			t.Errorf("test_%d: wrong readingTime. expected=%d. got=%d", i, tt.expected, m)
+		}
+	}
+}
+
+func repeatWords(n int) string {
+	words := make([]byte, 0, n*5)
+	for range n {
+		words = append(words, "word "...)
+	}
+	return string(words)
+}
+
+func testPages() []*PageContext {
+	return []*PageContext{
+		{Page: &Page{
+			Title:  "b",
+			Date:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			Tags:   []string{"go"},
+			Weight: 2,
+			Params: map[string]any{"author": "x"},
+		}},
+		{Page: &Page{
+			Title:  "a",
+			Date:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			Tags:   []string{"go", "web"},
+			Weight: 3,
+			Params: map[string]any{},
+		}},
+		{Page: &Page{
+			Title:  "c",
+			Date:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Weight: 1,
+			Params: map[string]any{"author": "y"},
+		}},
+	}
+}
+
+func pageTitles(t *testing.T, pages any) []string {
+	list, ok := pages.([]*PageContext)
+	if !ok {
+		t.Fatalf("expected []*PageContext. got %T", pages)
+	}
+	titles := make([]string, len(list))
+	for i, page := range list {
+		titles[i] = page.Title
+	}
+	return titles
+}
+
+func TestListFuncs(t *testing.T) {
+	pages := testPages()
+
+	tests := []struct {
+		fn       func() (any, error)
+		expected []string
+		err      error
+	}{
+		{func() (any, error) { return where(pages, "Title", "a") }, []string{"a"}, nil},
+		{func() (any, error) { return where(pages, "Tags", "go") }, []string{"b", "a"}, nil},
+		{func() (any, error) { return where(pages, "Params.author", "y") }, []string{"c"}, nil},
+		{func() (any, error) { return where(pages, "Author", "x") }, []string{"b"}, nil},
+		{func() (any, error) { return where(pages, "Weight", 1) }, []string{"c"}, nil},
+		{func() (any, error) { return where(pages, "dne", 1) }, []string{}, nil},
+		{func() (any, error) { return sortBy(pages, "Title") }, []string{"a", "b", "c"}, nil},
+		{func() (any, error) { return sortBy(pages, "Date", "desc") }, []string{"b", "c", "a"}, nil},
+		{func() (any, error) { return sortBy(pages, "Weight", "asc") }, []string{"c", "b", "a"}, nil},
+		{func() (any, error) { return sortBy(pages, "Params.author") }, []string{"b", "c", "a"}, nil},
+		{
+			func() (any, error) { return sortBy(pages, "Title", "up") },
+			nil,
+			fmt.Errorf("invalid sort order up. expected asc or desc"),
+		},
+		{func() (any, error) { return first(2, pages) }, []string{"b", "a"}, nil},
+		{func() (any, error) { return first(10, pages) }, []string{"b", "a", "c"}, nil},
+		{func() (any, error) { return where("x", "Title", "a") }, nil, fmt.Errorf("expected a list. got string")},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			result, err := tt.fn()
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%v. got=%v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if titles := pageTitles(t, result); !reflect.DeepEqual(titles, tt.expected) {
+				t.Errorf("wrong pages. expected=%v. got=%v", tt.expected, titles)
+			}
+		})
+	}
+
+	if len(pageTitles(t, pages)) != 3 || pages[0].Title != "b" {
+		t.Errorf("sortBy changed its input")
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	other := struct{ Year int }{2024}
+
+	tests := []struct {
+		a, b     any
+		expected int
+	}{
+		{date, date.AddDate(0, 0, 1), -1},
+		{date.AddDate(1, 0, 0), date, 1},
+		{1, 2, -1},
+		// structs that aren't dates are compared by how they're printed
+		{date, other, cmp.Compare(fmt.Sprint(date), fmt.Sprint(other))},
+		{other, date, cmp.Compare(fmt.Sprint(other), fmt.Sprint(date))},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			result := compareValues(reflect.ValueOf(tt.a), reflect.ValueOf(tt.b))
+			if result != tt.expected {
+				t.Errorf("wrong result. expected=%d got=%d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGroupByYear(t *testing.T) {
+	groups := groupByYear(testPages())
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups. got %d", len(groups))
+	}
+	if groups[0].Year != 2024 || !reflect.DeepEqual(pageTitles(t, groups[0].Pages), []string{"b", "c"}) {
+		t.Errorf("wrong first group. got=%d %v", groups[0].Year, pageTitles(t, groups[0].Pages))
+	}
+	if groups[1].Year != 2023 || !reflect.DeepEqual(pageTitles(t, groups[1].Pages), []string{"a"}) {
+		t.Errorf("wrong second group. got=%d %v", groups[1].Year, pageTitles(t, groups[1].Pages))
+	}
+}
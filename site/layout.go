@@ -0,0 +1,246 @@
+package site
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// the default layouts. a site overrides one of them by adding a file with
+// the same name to layouts/. a theme can do the same in
+// themes/<theme>/layouts/. the site's layouts are used over the theme's
+//
+//go:embed templates
+var embeddedLayouts embed.FS
+
+const layoutsDir = "layouts"
+
+// the layouts that pages are rendered with. every other layout (base.html,
+// partials/, etc) is shared by all of them
+const (
+	blogLayout     = "blog.html"
+	indexLayout    = "index.html"
+	sectionLayout  = "section.html"
+	taxonomyLayout = "taxonomy.html"
+	termLayout     = "term.html"
+)
+
+var pageLayouts = []string{
+	blogLayout,
+	indexLayout,
+	sectionLayout,
+	taxonomyLayout,
+	termLayout,
+}
+
+type layouts struct {
+	blog  *template.Template
+	index *template.Template
+	// section lists the pages and subsections of a directory in content/
+	section *template.Template
+	// taxonomy lists the terms of a taxonomy and term lists the pages
+	// of a term
+	taxonomy *template.Template
+	term     *template.Template
+}
+
+var defaultLayouts = mustLoadDefaultLayouts()
+
+func mustLoadDefaultLayouts() layouts {
+	files, err := embeddedLayoutFiles()
+	if err != nil {
+		panic(err)
+	}
+	l, err := files.compile(templateFuncs(SiteConfig{}))
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+type layoutFile struct {
+	// path of the file the layout was read from. empty for embedded layouts
+	source  string
+	content string
+}
+
+// layout names like partials/head.html mapped to their files
+type layoutFiles map[string]layoutFile
+
+func embeddedLayoutFiles() (layoutFiles, error) {
+	files := make(layoutFiles)
+	err := fs.WalkDir(
+		embeddedLayouts,
+		"templates",
+		func(name string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := embeddedLayouts.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			files[strings.TrimPrefix(name, "templates/")] = layoutFile{
+				content: string(content),
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the embedded layouts: %w", err)
+	}
+	return files, nil
+}
+
+// loadLayouts compiles the embedded layouts with the ones in the site and
+// the theme in config. config.Theme is the path of the theme's stylesheet
+// e.g. /themes/dark.css
+func loadLayouts(entries []Entry, config SiteConfig) (layouts, error) {
+	files, err := embeddedLayoutFiles()
+	if err != nil {
+		return layouts{}, err
+	}
+
+	if config.Theme != "" {
+		themeDir := strings.TrimSuffix(strings.TrimPrefix(config.Theme, "/"), ".css")
+		files.add(findEntry(entries, path.Join(themeDir, layoutsDir)))
+	}
+	files.add(findEntry(entries, layoutsDir))
+
+	return files.compile(templateFuncs(config))
+}
+
+// add adds every html file in dir. dir can be nil
+func (files layoutFiles) add(dir Entry) {
+	if dir == nil {
+		return
+	}
+	prefix := dir.Name() + "/"
+
+	var walk func(entry Entry)
+	walk = func(entry Entry) {
+		if entry.Type() == DirectoryEntry {
+			for _, child := range entry.Children() {
+				walk(child)
+			}
+			return
+		}
+		if path.Ext(entry.Name()) != ".html" {
+			return
+		}
+		files[strings.TrimPrefix(entry.Name(), prefix)] = layoutFile{
+			source:  entry.Name(),
+			content: string(entry.Content()),
+		}
+	}
+	walk(dir)
+}
+
+func (files layoutFiles) compile(funcs template.FuncMap) (layouts, error) {
+	var l layouts
+	pages := []struct {
+		name string
+		tmpl **template.Template
+	}{
+		{blogLayout, &l.blog},
+		{indexLayout, &l.index},
+		{sectionLayout, &l.section},
+		{taxonomyLayout, &l.taxonomy},
+		{termLayout, &l.term},
+	}
+	for _, page := range pages {
+		tmpl, err := files.page(page.name, funcs)
+		if err != nil {
+			return layouts{}, err
+		}
+		*page.tmpl = tmpl
+	}
+	return l, nil
+}
+
+// page parses every shared layout and then the page's own layout so that
+// the blocks it defines replace the ones in base.html
+func (files layoutFiles) page(
+	name string,
+	funcs template.FuncMap,
+) (*template.Template, error) {
+	names := make([]string, 0, len(files))
+	for n := range files {
+		if !slices.Contains(pageLayouts, n) {
+			names = append(names, n)
+		}
+	}
+	slices.Sort(names)
+	names = append(names, name)
+
+	tmpl := template.New(name).Funcs(funcs)
+	for _, n := range names {
+		t := tmpl
+		if n != name {
+			t = tmpl.New(n)
+		}
+		_, err := t.Parse(files[n].content)
+		if err != nil {
+			return nil, newLayoutError(files[n].source, err)
+		}
+	}
+	return tmpl, nil
+}
+
+func findEntry(entries []Entry, name string) Entry {
+	for _, entry := range entries {
+		if entry.Name() == name {
+			return entry
+		}
+		if entry.Type() == DirectoryEntry && strings.HasPrefix(name, entry.Name()+"/") {
+			return findEntry(entry.Children(), name)
+		}
+	}
+	return nil
+}
+
+// isLayoutsDir reports whether name is a directory of layouts.
+// layouts are only used to build the site
+func isLayoutsDir(name string) bool {
+	if name == layoutsDir {
+		return true
+	}
+	matched, _ := path.Match("themes/*/"+layoutsDir, name)
+	return matched
+}
+
+// LayoutError is returned when one of the site's layouts can't be parsed.
+// Line is 0 if it is unknown
+type LayoutError struct {
+	// File is empty if the error is in an embedded layout
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *LayoutError) Error() string {
+	return fmt.Sprintf("invalid layout %s: %s", e.File, e.Msg)
+}
+
+// template errors look like template: name:line: msg
+var templateErrorRegexp = regexp.MustCompile(`^template: [^:]*:(\d+):(?:\d+:)? (.*)`)
+
+func newLayoutError(file string, err error) error {
+	layoutErr := &LayoutError{File: file, Msg: err.Error()}
+	var tmplErr *template.Error
+	if errors.As(err, &tmplErr) {
+		layoutErr.Line = tmplErr.Line
+		layoutErr.Msg = tmplErr.Description
+	} else if match := templateErrorRegexp.FindStringSubmatch(err.Error()); match != nil {
+		layoutErr.Line, _ = strconv.Atoi(match[1])
+		layoutErr.Msg = match[2]
+	}
+	return layoutErr
+}
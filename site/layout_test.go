@@ -0,0 +1,133 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"testing"
+)
+
+func TestLoadLayouts(t *testing.T) {
+	md := "+++\ntitle = \"a post\"\ndate = 2000-01-01\n+++\nhello"
+
+	tests := []struct {
+		entries  []Entry
+		contains []string
+		excludes []string
+		err      error
+	}{
+		{
+			nil,
+			[]string{`<a href="/">test blog</a>`, "<h1>a post</h1>", "<p>hello</p>"},
+			nil,
+			nil,
+		},
+		{
+			addFileEntries(nil, map[string]string{
+				"layouts/partials/header.html": `<header>{{.Site.Title}}</header>`,
+			}),
+			[]string{"<header>test blog</header>", "<h1>a post</h1>"},
+			[]string{`<div id="site-title">`},
+			nil,
+		},
+		{
+			addFileEntries(nil, map[string]string{
+				"layouts/blog.html": `{{template "base.html" .}}{{define "main"}}<main>{{.Page.Content}}</main>{{end}}`,
+			}),
+			[]string{"<main><p>hello</p>\n</main>", `<a href="/">test blog</a>`},
+			[]string{"<h1>a post</h1>"},
+			nil,
+		},
+		{
+			addFileEntries(nil, map[string]string{
+				"layouts/blog.html": `<p>{{.Page.Title}}</p>`,
+			}),
+			[]string{"<p>a post</p>"},
+			[]string{"<html"},
+			nil,
+		},
+		{
+			addFileEntries(nil, map[string]string{
+				"themes/dark/layouts/partials/footer.html": `<footer>theme</footer>`,
+				"themes/dark/layouts/partials/header.html": `<header>theme</header>`,
+				"layouts/partials/header.html":             `<header>site</header>`,
+			}),
+			[]string{"<footer>theme</footer>", "<header>site</header>"},
+			[]string{"<header>theme</header>"},
+			nil,
+		},
+		{
+			addFileEntries(nil, map[string]string{
+				"layouts/partials/header.html": "<header>\n{{.Site.Title}\n</header>",
+			}),
+			nil,
+			nil,
+			fmt.Errorf("invalid layout layouts/partials/header.html: bad character U+007D '}'"),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			l, err := loadLayouts(tt.entries, SiteConfig{Theme: "/themes/dark.css"})
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%v. got=%v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			doc := mdToHTML(t, md)
+			html, err := renderLayout(l.blog, Context{
+				Site: &SiteContext{Title: "test blog"},
+				Page: &PageContext{Page: docPage(t, doc), Content: template.HTML(doc.Content)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, s := range tt.contains {
+				if !bytes.Contains(html, []byte(s)) {
+					t.Errorf("expected html to contain %q. got=\n%s", s, html)
+				}
+			}
+			for _, s := range tt.excludes {
+				if bytes.Contains(html, []byte(s)) {
+					t.Errorf("expected html not to contain %q. got=\n%s", s, html)
+				}
+			}
+		})
+	}
+}
+
+func TestLayoutDiagnostics(t *testing.T) {
+	entries := siteEntries("", map[string]string{
+		"layouts/base.html": "<html>\n{{if .Title}}\n</html>",
+	})
+
+	_, err := BuildFromEntries(entries, BuildOptions{})
+	expected := "layouts/base.html:3: error: invalid layout: unexpected EOF"
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong err. expected=%q. got=%q", expected, err)
+	}
+}
+
+func TestLayoutsArentPublished(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"layouts", false},
+		{"themes/dark/layouts", false},
+		{"content/layouts", true},
+		{"themes/dark.css", true},
+		{"ssg.toml", false},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if IsPublished(Node{Name: tt.name}) != tt.expected {
+				t.Errorf("IsPublished(%s) should be %t", tt.name, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package site
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// MenuEntry is an entry of a menu in ssg.toml. e.g.
+//
+//	[[menu.main]]
+//	name = "About"
+//	page = "about.md"
+type MenuEntry struct {
+	// Name defaults to the title of Page
+	Name string `toml:"name,omitempty"`
+	URL  string `toml:"url,omitempty"`
+	// Page is the path of a markdown file in the site. e.g. about.md.
+	// it's used instead of URL
+	Page   string `toml:"page,omitempty"`
+	Weight int    `toml:"weight,omitempty"`
+}
+
+// SocialLink is a [[social]] entry in ssg.toml
+type SocialLink struct {
+	Name string `toml:"name"`
+	URL  string `toml:"url"`
+}
+
+// MenuItem is an entry of a menu in .Site.Menus
+type MenuItem struct {
+	Name   string
+	URL    string
+	Weight int
+	// Page is the page the item links to. nil for other links
+	Page *PageContext
+}
+
+func validateMenus(config SiteConfig) error {
+	for _, menu := range slices.Sorted(maps.Keys(config.Menus)) {
+		for i, entry := range config.Menus[menu] {
+			switch {
+			case entry.URL == "" && entry.Page == "":
+				return fmt.Errorf("menu.%s entry %d needs a url or a page", menu, i+1)
+			case entry.URL != "" && entry.Page != "":
+				return fmt.Errorf("menu.%s entry %d can't have both a url and a page", menu, i+1)
+			case entry.Name == "" && entry.Page == "":
+				return fmt.Errorf("menu.%s entry %d needs a name", menu, i+1)
+			}
+		}
+	}
+
+	for i, link := range config.Social {
+		if link.Name == "" || link.URL == "" {
+			return fmt.Errorf("social entry %d needs a name and a url", i+1)
+		}
+	}
+	return nil
+}
+
+// resolveMenus creates the menus in ssg.toml and adds the pages that
+// set menu in their front matter to them. items are sorted by weight.
+// entries of pages that aren't built (e.g. drafts) are skipped and pages
+// that are already in a menu aren't added again
+func resolveMenus(
+	config SiteConfig,
+	entries []Entry,
+	site *SiteContext,
+	pages map[string]*PageContext,
+) (map[string][]MenuItem, error) {
+	menus := make(map[string][]MenuItem)
+
+	for _, menu := range slices.Sorted(maps.Keys(config.Menus)) {
+		for _, entry := range config.Menus[menu] {
+			item := MenuItem{
+				Name:   entry.Name,
+				URL:    relURL("", entry.URL),
+				Weight: entry.Weight,
+			}
+
+			if entry.Page != "" {
+				name := strings.TrimPrefix(entry.Page, "/")
+				page, ok := pages[name]
+				if !ok {
+					if findEntry(entries, name) == nil {
+						return nil, fmt.Errorf(
+							"menu.%s: page %s not found",
+							menu,
+							entry.Page,
+						)
+					}
+					continue
+				}
+				item.Page = page
+				item.URL = page.RelPermalink
+				if item.Name == "" {
+					item.Name = page.Title
+				}
+			}
+
+			menus[menu] = append(menus[menu], item)
+		}
+	}
+
+	for _, page := range site.Pages {
+		for _, menu := range page.Menus {
+			// an entry in ssg.toml for the same page replaces the front matter
+			if hasMenuItem(menus[menu], page.RelPermalink) {
+				continue
+			}
+			menus[menu] = append(menus[menu], MenuItem{
+				Name:   page.Title,
+				URL:    page.RelPermalink,
+				Weight: page.Weight,
+				Page:   page,
+			})
+		}
+	}
+
+	for _, items := range menus {
+		slices.SortStableFunc(items, func(a, b MenuItem) int {
+			return cmp.Compare(a.Weight, b.Weight)
+		})
+	}
+
+	return menus, nil
+}
+
+// hasMenuItem reports whether one of items links to url
+func hasMenuItem(items []MenuItem, url string) bool {
+	return slices.ContainsFunc(items, func(item MenuItem) bool {
+		return item.URL == url
+	})
+}
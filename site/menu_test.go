@@ -0,0 +1,189 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestParseConfigMenus(t *testing.T) {
+	tests := []struct {
+		ssgToml string
+		menus   map[string][]MenuEntry
+		social  []SocialLink
+		err     error
+	}{
+		{
+			defaultSsgToml() + `
+[[menu.main]]
+name = "About"
+page = "about.md"
+weight = 2
+
+[[menu.main]]
+name = "GitHub"
+url = "https://github.com"
+
+[[social]]
+name = "GitHub"
+url = "https://github.com/someone"
+`,
+			map[string][]MenuEntry{
+				"main": {
+					{Name: "About", Page: "about.md", Weight: 2},
+					{Name: "GitHub", URL: "https://github.com"},
+				},
+			},
+			[]SocialLink{{Name: "GitHub", URL: "https://github.com/someone"}},
+			nil,
+		},
+		{
+			defaultSsgToml() + "[[menu.main]]\nname = \"a\"\n",
+			nil, nil,
+			fmt.Errorf("menu.main entry 1 needs a url or a page"),
+		},
+		{
+			defaultSsgToml() + "[[menu.main]]\nurl = \"/a\"\npage = \"a.md\"\n",
+			nil, nil,
+			fmt.Errorf("menu.main entry 1 can't have both a url and a page"),
+		},
+		{
+			defaultSsgToml() + "[[menu.main]]\nurl = \"/a\"\n",
+			nil, nil,
+			fmt.Errorf("menu.main entry 1 needs a name"),
+		},
+		{
+			defaultSsgToml() + "[[social]]\nname = \"a\"\n",
+			nil, nil,
+			fmt.Errorf("social entry 1 needs a name and a url"),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			config, err := parseConfig(
+				[]Entry{defaultThemeDirEntry()},
+				[]byte(tt.ssgToml),
+			)
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%v. got=%v", tt.err, err)
+			}
+			if !reflect.DeepEqual(config.Menus, tt.menus) {
+				t.Errorf("wrong menus. expected=%+v. got=%+v", tt.menus, config.Menus)
+			}
+			if !reflect.DeepEqual(config.Social, tt.social) {
+				t.Errorf("wrong social. expected=%+v. got=%+v", tt.social, config.Social)
+			}
+		})
+	}
+}
+
+func TestMenus(t *testing.T) {
+	config := `
+[[menu.main]]
+page = "about.md"
+weight = 2
+
+[[menu.main]]
+name = "Drafts"
+page = "content/draft.md"
+
+[[menu.main]]
+name = "GitHub"
+url = "https://github.com"
+weight = 3
+
+[[social]]
+name = "Mastodon"
+url = "https://example.social/@someone"
+`
+	entries := siteEntries(config, map[string]string{
+		"about.md":         "+++\ntitle = \"About me\"\ndate = 2024-01-01\n+++\n",
+		"content/post.md":  "+++\ntitle = \"Posts\"\ndate = 2024-01-01\nmenu = \"main\"\nweight = 1\n+++\n",
+		"content/draft.md": "+++\ntitle = \"draft\"\ndate = 2024-01-01\ndraft = true\n+++\n",
+	})
+
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var index []byte
+	for _, node := range s.Nodes {
+		if node.Name == "index.html" {
+			index = node.Content
+		}
+	}
+
+	expected := []string{
+		`<a class="menu-item" href="/content/post/">Posts</a>`,
+		`<a class="menu-item" href="/about/">About me</a>`,
+		`<a class="menu-item" href="https://github.com">GitHub</a>`,
+		`<a class="social-link" href="https://example.social/@someone">Mastodon</a>`,
+	}
+	last := -1
+	for _, s := range expected {
+		i := bytes.Index(index, []byte(s))
+		if i == -1 {
+			t.Fatalf("expected index.html to contain %s. got=\n%s", s, index)
+		}
+		if i < last {
+			t.Errorf("%s is in the wrong order", s)
+		}
+		last = i
+	}
+	if bytes.Contains(index, []byte("Drafts")) {
+		t.Errorf("menu entries of drafts should be skipped")
+	}
+}
+
+func TestMenuDuplicates(t *testing.T) {
+	config := `
+[[menu.main]]
+name = "Home"
+url = "/"
+
+[[menu.main]]
+page = "about.md"
+weight = 1
+
+[[menu.main]]
+name = "Posts"
+url = "/content/post/"
+weight = 2
+`
+	entries := siteEntries(config, map[string]string{
+		"about.md":        "+++\ntitle = \"About\"\ndate = 2024-01-01\nmenu = \"main\"\n+++\n",
+		"content/post.md": "+++\ntitle = \"post\"\ndate = 2024-01-01\nmenu = \"main\"\n+++\n",
+	})
+
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := findNode(s.Nodes, "index.html")
+	if index == nil {
+		t.Fatal("index.html wasn't generated")
+	}
+
+	// pages that are in ssg.toml and set menu are only listed once
+	expected := `<a class="menu-item" href="/">Home</a>` +
+		`<a class="menu-item" href="/about/">About</a>` +
+		`<a class="menu-item" href="/content/post/">Posts</a>`
+	got := regexp.MustCompile(`<a class="menu-item"[^>]*>[^<]*</a>`).FindAll(index.Content, -1)
+	if string(bytes.Join(got, nil)) != expected {
+		t.Errorf("wrong menu.\nexpected=%s\n     got=%s", expected, bytes.Join(got, nil))
+	}
+}
+
+func TestMenuPageNotFound(t *testing.T) {
+	entries := siteEntries("[[menu.main]]\npage = \"dne.md\"\n", nil)
+
+	_, err := BuildFromEntries(entries, BuildOptions{})
+	expected := "ssg.toml: error: menu.main: page dne.md not found"
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong err. expected=%q. got=%q", expected, err)
+	}
+}
@@ -0,0 +1,239 @@
+package site
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"time"
+)
+
+// Page is the metadata of a page built from a markdown file.
+// it's validated once when the file is built
+type Page struct {
+	Title string
+	Date  time.Time
+	// Lastmod is the date the page was last changed.
+	// it's the same as Date if the front matter doesn't set it
+	Lastmod     time.Time
+	Draft       bool
+	Tags        []string
+	Description string
+	// Slug replaces the file name in the page's URL. empty if not set
+	Slug string
+	// Weight orders the page in lists like menus. lower weights are first
+	Weight int
+	// Menus are the names of the menus the page is in
+	Menus []string
+	// Sitemap is false if the page is left out of sitemap.xml
+	Sitemap bool
+	// Aliases are the old urls of the page. they redirect to it
+	Aliases []string
+	// Params has every key in the front matter that isn't one of the fields
+	// above. values have the types described in markdown.HTMLDoc
+	Params map[string]any
+}
+
+// keys of the front matter that are stored in Page's fields
+var pageKeys = []string{
+	"title",
+	"date",
+	"lastmod",
+	"draft",
+	"tags",
+	"description",
+	"slug",
+	"weight",
+	"menu",
+	"sitemap",
+	"aliases",
+}
+
+// newPage validates the front matter of the page built from the markdown
+// file called source. title is required and so is date unless source is an
+// index.md file. dates without a time zone are in loc
+func newPage(metadata map[string]any, loc *time.Location, source string) (*Page, error) {
+	page := &Page{Sitemap: true, Params: make(map[string]any)}
+
+	title, ok, err := metadataString(metadata, "title")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("blog title not found")
+	}
+	page.Title = title
+
+	// index pages are lists so they don't need a date
+	if date, ok := metadata["date"]; ok {
+		page.Date, err = parseDate(date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %w", err)
+		}
+	} else if !isIndexPage(source) {
+		return nil, fmt.Errorf("blog date not found")
+	}
+
+	page.Lastmod = page.Date
+	if lastmod, ok := metadata["lastmod"]; ok {
+		page.Lastmod, err = parseDate(lastmod, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lastmod: %w", err)
+		}
+	}
+
+	if draft, ok, err := metadataBool(metadata, "draft"); err != nil {
+		return nil, err
+	} else if ok {
+		page.Draft = draft
+	}
+
+	if tags, ok := metadata["tags"]; ok {
+		page.Tags, err = stringList(tags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tags: %w", err)
+		}
+	}
+
+	page.Description, _, err = metadataString(metadata, "description")
+	if err != nil {
+		return nil, err
+	}
+
+	page.Slug, _, err = metadataString(metadata, "slug")
+	if err != nil {
+		return nil, err
+	}
+
+	if weight, ok := metadata["weight"]; ok {
+		page.Weight, ok = integer(weight)
+		if !ok {
+			return nil, fmt.Errorf("weight has to be an integer. got %v", weight)
+		}
+	}
+
+	if menu, ok := metadata["menu"]; ok {
+		page.Menus, err = stringList(menu)
+		if err != nil {
+			return nil, fmt.Errorf("invalid menu: %w", err)
+		}
+	}
+
+	if sitemap, ok, err := metadataBool(metadata, "sitemap"); err != nil {
+		return nil, err
+	} else if ok {
+		page.Sitemap = sitemap
+	}
+
+	if aliases, ok := metadata["aliases"]; ok {
+		page.Aliases, err = stringList(aliases)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aliases: %w", err)
+		}
+		for _, alias := range page.Aliases {
+			if !strings.HasPrefix(alias, "/") || strings.HasPrefix(alias, "//") {
+				return nil, fmt.Errorf("invalid alias %q. aliases are paths that start with /", alias)
+			}
+		}
+	}
+
+	for key, value := range metadata {
+		if !isPageKey(key) {
+			page.Params[key] = value
+		}
+	}
+
+	return page, nil
+}
+
+// isIndexPage reports whether the markdown file called name is an
+// index.md
+func isIndexPage(name string) bool {
+	return path.Base(name) == "index.md"
+}
+
+func isPageKey(key string) bool {
+	for _, k := range pageKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Author returns the author in the page's front matter.
+// empty if it isn't set
+func (p *Page) Author() string {
+	author, _ := p.Params["author"].(string)
+	return author
+}
+
+// metadataString returns the string value of key.
+// returns false if key isn't set
+func metadataString(metadata map[string]any, key string) (string, bool, error) {
+	value, ok := metadata[key]
+	if !ok {
+		return "", false, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", true, fmt.Errorf("%s has to be a string. got %v", key, value)
+	}
+	return s, true, nil
+}
+
+// metadataBool returns the boolean value of key.
+// returns false if key isn't set
+func metadataBool(metadata map[string]any, key string) (bool, bool, error) {
+	value, ok := metadata[key]
+	if !ok {
+		return false, false, nil
+	}
+	switch value {
+	// strings are used by metadata that isn't TOML
+	case true, "true":
+		return true, true, nil
+	case false, "false":
+		return false, true, nil
+	}
+	return false, true, fmt.Errorf(
+		"Invalid value for %s %v. expected true or false",
+		key,
+		value,
+	)
+}
+
+// integer accepts whole numbers of any type. YAML and JSON numbers can be
+// floats like 3.0
+func integer(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// stringList accepts a list of strings or a single string
+func stringList(value any) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []any:
+		list := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of strings. got %v", value)
+			}
+			list[i] = s
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("expected a list of strings. got %v", value)
+}
@@ -0,0 +1,204 @@
+package site
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewPage(t *testing.T) {
+	tests := []struct {
+		md       string
+		expected *Page
+		err      error
+	}{
+		{
+			`+++
+title = "A post"
+date = 2024-01-02
+lastmod = 2024-02-03
+draft = true
+tags = ["go", "web"]
+description = "about a post"
+slug = "a-post"
+weight = 3
+author = "Hassan"
+
+[cover]
+image = "cover.png"
++++
+`,
+			&Page{
+				Title:       "A post",
+				Date:        time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Lastmod:     time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+				Draft:       true,
+				Tags:        []string{"go", "web"},
+				Description: "about a post",
+				Slug:        "a-post",
+				Weight:      3,
+				Sitemap:     true,
+				Params: map[string]any{
+					"author": "Hassan",
+					"cover":  map[string]any{"image": "cover.png"},
+				},
+			},
+			nil,
+		},
+		{
+			"+++\ntitle = post\ndate = 01-01-2000\ntags = go\n+++\n",
+			&Page{
+				Title:   "post",
+				Date:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Lastmod: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Tags:    []string{"go"},
+				Sitemap: true,
+				Params:  map[string]any{},
+			},
+			nil,
+		},
+		{"+++\ntitle = post\n+++\n", nil, fmt.Errorf("blog date not found")},
+		{"+++\ndate = 2000-01-01\n+++\n", nil, fmt.Errorf("blog title not found")},
+		{
+			"+++\ntitle = 1\ndate = 2000-01-01\n+++\n",
+			nil,
+			fmt.Errorf("title has to be a string. got 1"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = true\n+++\n",
+			nil,
+			fmt.Errorf("invalid date: true is not a date"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\ntags = [1]\n+++\n",
+			nil,
+			fmt.Errorf("invalid tags: expected a list of strings. got [1]"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\nmenu = [\"main\", \"footer\"]\n+++\n",
+			&Page{
+				Title:   "a",
+				Date:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Lastmod: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Menus:   []string{"main", "footer"},
+				Sitemap: true,
+				Params:  map[string]any{},
+			},
+			nil,
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\nmenu = 1\n+++\n",
+			nil,
+			fmt.Errorf("invalid menu: expected a list of strings. got 1"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\nweight = \"heavy\"\n+++\n",
+			nil,
+			fmt.Errorf("weight has to be an integer. got heavy"),
+		},
+		{
+			"---\ntitle: a\ndate: 2000-01-01\nweight: 2.0\n---\n",
+			&Page{
+				Title:   "a",
+				Date:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Lastmod: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Weight:  2,
+				Sitemap: true,
+				Params:  map[string]any{},
+			},
+			nil,
+		},
+		{
+			`{"title": "a", "date": "01-01-2000", "weight": -1}`,
+			&Page{
+				Title:   "a",
+				Date:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Lastmod: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Weight:  -1,
+				Sitemap: true,
+				Params:  map[string]any{},
+			},
+			nil,
+		},
+		{
+			"---\ntitle: a\ndate: 2000-01-01\nweight: 1.5\n---\n",
+			nil,
+			fmt.Errorf("weight has to be an integer. got 1.5"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\nsitemap = false\n+++\n",
+			&Page{
+				Title:   "a",
+				Date:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Lastmod: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Params:  map[string]any{},
+			},
+			nil,
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\nsitemap = \"no\"\n+++\n",
+			nil,
+			fmt.Errorf("Invalid value for sitemap no. expected true or false"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\naliases = [\"/old/\", \"/posts/a.html\"]\n+++\n",
+			&Page{
+				Title:   "a",
+				Date:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Lastmod: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Sitemap: true,
+				Aliases: []string{"/old/", "/posts/a.html"},
+				Params:  map[string]any{},
+			},
+			nil,
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\naliases = [\"old/\"]\n+++\n",
+			nil,
+			fmt.Errorf("invalid alias \"old/\". aliases are paths that start with /"),
+		},
+		{
+			"+++\ntitle = \"a\"\ndate = 2000-01-01\naliases = [\"//example.com/\"]\n+++\n",
+			nil,
+			fmt.Errorf("invalid alias \"//example.com/\". aliases are paths that start with /"),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			doc := mdToHTML(t, tt.md)
+			page, err := newPage(doc.Metadata, time.UTC, "content/post.md")
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%v got=%v", tt.err, err)
+			}
+			if !reflect.DeepEqual(page, tt.expected) {
+				t.Errorf("wrong page.\nexpected=%+v\n     got=%+v", tt.expected, page)
+			}
+		})
+	}
+}
+
+func TestNewIndexPage(t *testing.T) {
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{"index.md", nil},
+		{"content/go/index.md", nil},
+		{"content/index-of-posts.md", fmt.Errorf("blog date not found")},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			doc := mdToHTML(t, "+++\ntitle = \"a\"\n+++\n")
+			page, err := newPage(doc.Metadata, time.UTC, tt.source)
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%v got=%v", tt.err, err)
+			}
+			if err == nil && !page.Date.IsZero() {
+				t.Errorf("expected the page to have no date. got %v", page.Date)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package site
+
+import (
+	"html/template"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Paginator is one page of a list like index.html. .Pages is the same as
+// .Paginator.Pages in list layouts
+type Paginator struct {
+	// Current is the number of this page. the first page is 1
+	Current int
+	Total   int
+	// Pages are the pages listed on this page
+	Pages []*PageContext
+	// PrevURL is the url of the page before this one and NextURL is the
+	// url of the one after it. empty if there isn't one
+	PrevURL string
+	NextURL string
+	// FirstURL and LastURL are the urls of the first and last pages
+	FirstURL string
+	LastURL  string
+}
+
+// pagerURL returns the url of page n of the list at relPermalink.
+// the first page is at relPermalink and the rest are at
+// <relPermalink>page/<n>/
+func pagerURL(relPermalink string, n int) string {
+	if n == 1 {
+		return relPermalink
+	}
+	return path.Join(relPermalink, "page", strconv.Itoa(n)) + "/"
+}
+
+// paginate splits pages into pages of size. every page is on the first
+// one if size is 0. there's always at least one page
+func paginate(pages []*PageContext, size int, relPermalink string) []*Paginator {
+	if size <= 0 {
+		size = max(len(pages), 1)
+	}
+	total := max((len(pages)+size-1)/size, 1)
+
+	paginators := make([]*Paginator, total)
+	for i := range paginators {
+		start := min(i*size, len(pages))
+		end := min(start+size, len(pages))
+		p := &Paginator{
+			Current:  i + 1,
+			Total:    total,
+			Pages:    pages[start:end:end],
+			FirstURL: pagerURL(relPermalink, 1),
+			LastURL:  pagerURL(relPermalink, total),
+		}
+		if i > 0 {
+			p.PrevURL = pagerURL(relPermalink, i)
+		}
+		if i < total-1 {
+			p.NextURL = pagerURL(relPermalink, i+2)
+		}
+		paginators[i] = p
+	}
+	return paginators
+}
+
+// renderList renders layout for every page of the list of pages. ctx.Page
+// is the list itself, its RelPermalink is where the first page is generated.
+// the content of ctx.Page is an intro so it's only on the first page
+func renderList(
+	layout *template.Template,
+	ctx Context,
+	pages []*PageContext,
+) ([]Node, error) {
+	list := ctx.Page
+	var nodes []Node
+	for _, paginator := range paginate(pages, ctx.Site.Config.Paginate, list.RelPermalink) {
+		relPermalink := pagerURL(list.RelPermalink, paginator.Current)
+		page := *list
+		page.RelPermalink = relPermalink
+		page.Permalink = absURL(ctx.Site.BaseURL, relPermalink)
+		if paginator.Current > 1 {
+			page.Content = ""
+		}
+
+		ctx.Page = &page
+		ctx.Pages = paginator.Pages
+		ctx.Paginator = paginator
+		content, err := renderLayout(layout, ctx)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, Node{
+			Name:    strings.TrimPrefix(relPermalink, "/") + "index.html",
+			Type:    HTMLNode,
+			Content: content,
+		})
+	}
+	return nodes, nil
+}
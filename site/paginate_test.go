@@ -0,0 +1,146 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	pages := make([]*PageContext, 5)
+	for i := range pages {
+		pages[i] = &PageContext{Page: &Page{Title: fmt.Sprint(i)}}
+	}
+
+	tests := []struct {
+		pages        []*PageContext
+		size         int
+		relPermalink string
+		// the titles on each page
+		expected []string
+		prevURLs []string
+		nextURLs []string
+	}{
+		{pages, 0, "/", []string{"0 1 2 3 4"}, []string{""}, []string{""}},
+		{nil, 2, "/", []string{""}, []string{""}, []string{""}},
+		{pages, 5, "/", []string{"0 1 2 3 4"}, []string{""}, []string{""}},
+		{
+			pages, 2, "/",
+			[]string{"0 1", "2 3", "4"},
+			[]string{"", "/", "/page/2/"},
+			[]string{"/page/2/", "/page/3/", ""},
+		},
+		{
+			pages, 3, "/tags/go/",
+			[]string{"0 1 2", "3 4"},
+			[]string{"", "/tags/go/"},
+			[]string{"/tags/go/page/2/", ""},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			paginators := paginate(tt.pages, tt.size, tt.relPermalink)
+			if len(paginators) != len(tt.expected) {
+				t.Fatalf("expected %d pages. got %d", len(tt.expected), len(paginators))
+			}
+			for j, p := range paginators {
+				if p.Current != j+1 || p.Total != len(tt.expected) {
+					t.Errorf("wrong page number. expected=%d/%d got=%d/%d",
+						j+1, len(tt.expected), p.Current, p.Total)
+				}
+				if titles := titles(p.Pages); titles != tt.expected[j] {
+					t.Errorf("wrong pages on page %d. expected=%q got=%q", j+1, tt.expected[j], titles)
+				}
+				if p.PrevURL != tt.prevURLs[j] {
+					t.Errorf("wrong prev url on page %d. expected=%q got=%q", j+1, tt.prevURLs[j], p.PrevURL)
+				}
+				if p.NextURL != tt.nextURLs[j] {
+					t.Errorf("wrong next url on page %d. expected=%q got=%q", j+1, tt.nextURLs[j], p.NextURL)
+				}
+				if p.FirstURL != tt.relPermalink {
+					t.Errorf("wrong first url. expected=%q got=%q", tt.relPermalink, p.FirstURL)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginatedLists(t *testing.T) {
+	posts := make(map[string]string)
+	for i := 1; i <= 5; i++ {
+		posts[fmt.Sprintf("content/%d.md", i)] = fmt.Sprintf(
+			"+++\ntitle = \"post %d\"\ndate = 2024-01-0%d\ntags = [\"go\"]\n+++\n",
+			i, i,
+		)
+	}
+	entries := siteEntries("paginate = 2\n", posts)
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		contains []string
+		missing  []string
+	}{
+		{
+			"index.html",
+			[]string{"post 5", "post 4", `<span class="pagination-current">1 / 3</span>`, `href="/page/2/"`},
+			[]string{"post 3", "pagination-prev"},
+		},
+		{
+			"page/2/index.html",
+			[]string{"post 3", "post 2", `<a class="pagination-prev" href="/">`, `<a class="pagination-next" href="/page/3/">`},
+			[]string{"post 4", "post 1"},
+		},
+		{
+			"page/3/index.html",
+			[]string{"post 1", `href="/page/2/"`},
+			[]string{"post 2", "pagination-next"},
+		},
+		{
+			"tags/go/page/3/index.html",
+			[]string{"post 1", `<a class="pagination-prev" href="/tags/go/page/2/">`},
+			[]string{"post 2"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			for _, str := range tt.contains {
+				if !bytes.Contains(node.Content, []byte(str)) {
+					t.Errorf("expected %s to contain %s. got=\n%s", tt.name, str, node.Content)
+				}
+			}
+			for _, str := range tt.missing {
+				if bytes.Contains(node.Content, []byte(str)) {
+					t.Errorf("%s shouldn't contain %s. got=\n%s", tt.name, str, node.Content)
+				}
+			}
+		})
+	}
+
+	if findNode(s.Nodes, "page/4/index.html") != nil {
+		t.Errorf("there should only be 3 pages")
+	}
+	if diagnostics := CheckEntries(entries, BuildOptions{}); diagnostics.HasErrors() {
+		t.Errorf("pagination links should exist. got %v", diagnostics)
+	}
+}
+
+func TestPaginateConfig(t *testing.T) {
+	_, err := parseConfig(
+		[]Entry{defaultThemeDirEntry()},
+		[]byte(defaultSsgToml()+"paginate = -1\n"),
+	)
+	expected := fmt.Errorf("paginate can't be negative. got -1")
+	if !errEqual(err, expected) {
+		t.Errorf("wrong err. expected=%v. got=%v", expected, err)
+	}
+}
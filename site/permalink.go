@@ -0,0 +1,150 @@
+package site
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// the tokens that can be used in the permalink patterns in ssg.toml
+var permalinkTokens = []string{
+	":year",
+	":month",
+	":day",
+	":section",
+	":slug",
+	":title",
+	":filename",
+}
+
+var permalinkTokenRegexp = regexp.MustCompile(`:[a-z]+`)
+
+func validatePermalinks(config SiteConfig) error {
+	for _, dir := range slices.Sorted(maps.Keys(config.Permalinks)) {
+		pattern := config.Permalinks[dir]
+		if !strings.HasPrefix(pattern, "/") {
+			return fmt.Errorf("permalinks.%s has to start with /. got %s", dir, pattern)
+		}
+		for _, token := range permalinkTokenRegexp.FindAllString(pattern, -1) {
+			if !slices.Contains(permalinkTokens, token) {
+				return fmt.Errorf(
+					"permalinks.%s: unknown token %s. expected one of %s",
+					dir,
+					token,
+					strings.Join(permalinkTokens, " "),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// fileSlug is the slugified name of the markdown file called name without
+// its extension. names that don't have anything to slugify are kept as is
+func fileSlug(name string) string {
+	base := strings.TrimSuffix(path.Base(name), markdownExtension)
+	if slug := Slugify(base); slug != "" {
+		return slug
+	}
+	return base
+}
+
+// dirSlug slugifies every directory in the path dir. directories that
+// don't have anything to slugify are kept as is
+func dirSlug(dir string) string {
+	elems := strings.Split(dir, "/")
+	for i, elem := range elems {
+		if slug := Slugify(elem); slug != "" {
+			elems[i] = slug
+		}
+	}
+	return strings.Join(elems, "/")
+}
+
+// pageSlug is the slug in the page's front matter or the slug of the file
+// it's built from
+func pageSlug(page *Page, source string) string {
+	if slug := Slugify(page.Slug); slug != "" {
+		return slug
+	}
+	return fileSlug(source)
+}
+
+// pageName returns the name of the node of page. source is the markdown
+// file it's built from. pages are at the pattern in config.Permalinks for
+// their top level directory or in a directory named after their slug in the
+// slugified directory of source. index.md files are at the url of their
+// directory
+func pageName(config SiteConfig, page *Page, source string) string {
+	if isIndexPage(source) {
+		return nodeName(source)
+	}
+
+	slug := pageSlug(page, source)
+	pattern, ok := config.Permalinks[section(source)]
+	if !ok {
+		return path.Join(dirSlug(path.Dir(source)), slug, "index.html")
+	}
+
+	name := strings.NewReplacer(
+		":year", fmt.Sprintf("%04d", page.Date.Year()),
+		":month", fmt.Sprintf("%02d", page.Date.Month()),
+		":day", fmt.Sprintf("%02d", page.Date.Day()),
+		":section", dirSlug(section(source)),
+		":slug", slug,
+		":title", Slugify(page.Title),
+		":filename", fileSlug(source),
+	).Replace(pattern)
+	name = strings.Trim(path.Clean(name), "/")
+	if path.Ext(name) == ".html" {
+		return name
+	}
+	return path.Join(name, "index.html")
+}
+
+// placePages moves the nodes of pages into the directories of their urls.
+// e.g. content/a.md is built into content/a/index.html. pages that would
+// replace another file of the site are reported. if two pages have the
+// same url the one whose file comes first wins
+func (sb *siteBuilder) placePages(nodes []Node) []Node {
+	var moved []Node
+	nodes = takeMisplaced(nodes, ".", &moved)
+	slices.SortFunc(moved, func(a, b Node) int {
+		return strings.Compare(a.Source, b.Source)
+	})
+	for _, node := range moved {
+		if other := findNode(nodes, node.Name); other != nil {
+			file := other.Source
+			if file == "" {
+				file = other.Name
+			}
+			sb.addError(node.Source, fmt.Errorf("the url /%s is already used by %s", node.Name, file))
+			continue
+		}
+		nodes = addNode(nodes, node)
+	}
+	return nodes
+}
+
+// takeMisplaced removes the nodes that aren't in dir from nodes and adds
+// them to moved. directories that are left empty are removed
+func takeMisplaced(nodes []Node, dir string, moved *[]Node) []Node {
+	kept := nodes[:0]
+	for _, node := range nodes {
+		if node.Type == DirectoryNode {
+			hadChildren := len(node.Children) > 0
+			node.Children = takeMisplaced(node.Children, node.Name, moved)
+			if hadChildren && len(node.Children) == 0 {
+				continue
+			}
+		} else if path.Dir(node.Name) != dir {
+			*moved = append(*moved, node)
+			continue
+		}
+		kept = append(kept, node)
+	}
+	return kept
+}
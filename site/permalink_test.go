@@ -0,0 +1,128 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPageName(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	permalinks := map[string]string{
+		"content": "/:year/:month/:slug/",
+		"notes":   "/:section/:day-:filename.html",
+	}
+
+	tests := []struct {
+		source     string
+		slug       string
+		permalinks map[string]string
+		expected   string
+	}{
+		{"about.md", "", nil, "about/index.html"},
+		{"content/My First Post.md", "", nil, "content/my-first-post/index.html"},
+		{"content/a.md", "Hello, World", nil, "content/hello-world/index.html"},
+		{"content/!!.md", "", nil, "content/!!/index.html"},
+		{"content/My Notes/note-one.md", "", nil, "content/my-notes/note-one/index.html"},
+		{"content/My Notes/!!/a.md", "", nil, "content/my-notes/!!/a/index.html"},
+		{"content/My Notes/index.md", "", nil, "content/my-notes/index.html"},
+		{"index.md", "", nil, "index.html"},
+		{"content/index.md", "ignored", permalinks, "content/index.html"},
+		{"content/a.md", "", permalinks, "2024/03/a/index.html"},
+		{"content/go/a.md", "post", permalinks, "2024/03/post/index.html"},
+		{"notes/Some Note.md", "", permalinks, "notes/05-some-note.html"},
+		{"My Notes/a.md", "", map[string]string{"My Notes": "/:section/:slug/"}, "my-notes/a/index.html"},
+		{"about.md", "", permalinks, "about/index.html"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			config := SiteConfig{Permalinks: tt.permalinks}
+			page := &Page{Title: "A Title", Date: date, Slug: tt.slug}
+			if name := pageName(config, page, tt.source); name != tt.expected {
+				t.Errorf("wrong name. expected=%q got=%q", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestPermalinksConfig(t *testing.T) {
+	tests := []struct {
+		permalinks string
+		expected   error
+	}{
+		{"content = \"/:year/:month/:day/:title/\"\n", nil},
+		{"content = \"/posts/:filename.html\"\nnotes = \"/:section/:slug/\"\n", nil},
+		{"content = \":slug/\"\n", fmt.Errorf("permalinks.content has to start with /. got :slug/")},
+		{
+			"content = \"/:year/:name/\"\n",
+			fmt.Errorf("permalinks.content: unknown token :name. expected one of :year :month :day :section :slug :title :filename"),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			_, err := parseConfig(
+				[]Entry{defaultThemeDirEntry()},
+				[]byte(defaultSsgToml()+"[permalinks]\n"+tt.permalinks),
+			)
+			if !errEqual(err, tt.expected) {
+				t.Errorf("wrong err. expected=%v. got=%v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestPermalinks(t *testing.T) {
+	entries := siteEntries("[permalinks]\ncontent = \"/:year/:month/:slug/\"\n", map[string]string{
+		"content/Hello World.md": "+++\ntitle = \"hello\"\ndate = 2024-01-02\n+++\n",
+		"content/b.md":           "+++\ntitle = \"b\"\ndate = 2024-02-01\nslug = \"Second Post\"\n+++\n[hello](/2024/01/hello-world/)",
+	})
+
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"2024/01/hello-world/index.html", "2024/02/second-post/index.html"} {
+		if findNode(s.Nodes, name) == nil {
+			t.Errorf("%s wasn't built", name)
+		}
+	}
+	if findNode(s.Nodes, "content") != nil {
+		t.Errorf("content/ shouldn't be built once its pages are moved")
+	}
+
+	index := findNode(s.Nodes, "index.html")
+	if index == nil {
+		t.Fatal("index.html wasn't generated")
+	}
+	for _, link := range []string{`href="/2024/02/second-post/"`, `href="/2024/01/hello-world/"`} {
+		if !bytes.Contains(index.Content, []byte(link)) {
+			t.Errorf("expected index.html to contain %s. got=\n%s", link, index.Content)
+		}
+	}
+
+	if diagnostics := CheckEntries(entries, BuildOptions{}); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics. got %v", diagnostics)
+	}
+}
+
+func TestPermalinkCollisions(t *testing.T) {
+	entries := siteEntries("", map[string]string{
+		"content/a.md":     "+++\ntitle = \"a\"\ndate = 2024-01-01\n+++\n",
+		"content/A!.md":    "+++\ntitle = \"b\"\ndate = 2024-02-01\n+++\n",
+		"content/post.md":  "+++\ntitle = \"c\"\ndate = 2024-03-01\n+++\n",
+		"content/other.md": "+++\ntitle = \"d\"\ndate = 2024-04-01\nslug = \"post\"\n+++\n",
+	})
+
+	diagnostics := CheckEntries(entries, BuildOptions{})
+	expected := Diagnostics{
+		{File: "content/a.md", Severity: SeverityError, Message: "the url /content/a/index.html is already used by content/A!.md"},
+		{File: "content/post.md", Severity: SeverityError, Message: "the url /content/post/index.html is already used by content/other.md"},
+	}
+	if fmt.Sprint(diagnostics) != fmt.Sprint(expected) {
+		t.Errorf("wrong diagnostics.\nexpected=%v\n     got=%v", expected, diagnostics)
+	}
+}
@@ -0,0 +1,184 @@
+package site
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Hassan-Ibrahim-1/go-ssg/markdown"
+)
+
+// sectionIndexName is the file that sets the title and intro text of the
+// listing of a directory
+const sectionIndexName = "_index.md"
+
+// Section is a directory under content/ that's listed by section.html
+type Section struct {
+	// Name is the path of the directory. e.g. content/notes
+	Name string
+	// Title is the title in the directory's _index.md or the name of the
+	// directory if it doesn't have one
+	Title       string
+	Description string
+	// Content is the html of the directory's _index.md
+	Content template.HTML
+	// Pages are the pages in the directory, newest first. pages in
+	// subsections aren't included
+	Pages []*PageContext
+	// Sections are the subsections of the section sorted by title
+	Sections     []*Section
+	RelPermalink string
+	// Permalink is RelPermalink with the site's base_url
+	Permalink string
+}
+
+func isSectionIndex(name string) bool {
+	return path.Base(name) == sectionIndexName
+}
+
+// buildSections creates a section for content/ and every directory under
+// it. content/ is listed by index.html so its url is / and its title is the
+// site's. nil if there's no content/. errors in _index.md files are
+// recorded and the section keeps its defaults
+func (sb *siteBuilder) buildSections(entries []Entry, site *SiteContext) *Section {
+	content := findEntry(entries, "content")
+	if content == nil || content.Type() != DirectoryEntry {
+		return nil
+	}
+	root := sb.buildSection(content, site, site.Title)
+	root.RelPermalink = "/"
+	root.Permalink = absURL(site.BaseURL, "/")
+	return root
+}
+
+// title is used if the directory's _index.md doesn't have one
+func (sb *siteBuilder) buildSection(dir Entry, site *SiteContext, title string) *Section {
+	// the url is the same as the urls of the pages in the directory
+	relPermalink := "/" + dirSlug(dir.Name()) + "/"
+	section := &Section{
+		Name:         dir.Name(),
+		Title:        title,
+		RelPermalink: relPermalink,
+		Permalink:    absURL(site.BaseURL, relPermalink),
+	}
+
+	if index := findEntry(dir.Children(), path.Join(dir.Name(), sectionIndexName)); index != nil {
+		err := section.parseIndex(index.Content())
+		if err != nil {
+			sb.addError(index.Name(), err)
+		}
+	}
+
+	for _, page := range site.Pages {
+		if path.Dir(page.File) == section.Name {
+			section.Pages = append(section.Pages, page)
+		}
+	}
+
+	for _, child := range dir.Children() {
+		if child.Type() != DirectoryEntry || strings.HasPrefix(path.Base(child.Name()), ".") {
+			continue
+		}
+		// directories of images and other files don't need a listing
+		if sub := sb.buildSection(child, site, path.Base(child.Name())); !sub.isEmpty() {
+			section.Sections = append(section.Sections, sub)
+		}
+	}
+	slices.SortStableFunc(section.Sections, func(a, b *Section) int {
+		return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	})
+	return section
+}
+
+// find returns the section of the directory called name. nil if it
+// isn't s or one of its subsections
+func (s *Section) find(name string) *Section {
+	if s.Name == name {
+		return s
+	}
+	for _, sub := range s.Sections {
+		if sub.Name == name || strings.HasPrefix(name, sub.Name+"/") {
+			return sub.find(name)
+		}
+	}
+	return nil
+}
+
+// isEmpty reports whether the section has nothing to list
+func (s *Section) isEmpty() bool {
+	return len(s.Pages) == 0 && len(s.Sections) == 0 && s.Content == ""
+}
+
+// page returns the page that lists the section. its title, description and
+// content come from the section's _index.md
+func (s *Section) page() *PageContext {
+	return &PageContext{
+		Page: &Page{
+			Title:       s.Title,
+			Description: s.Description,
+		},
+		Content:      s.Content,
+		Summary:      template.HTML(template.HTMLEscapeString(s.Description)),
+		RelPermalink: s.RelPermalink,
+		Permalink:    s.Permalink,
+		Section:      "content",
+	}
+}
+
+// parseIndex sets the title, description and content of the section from
+// its _index.md. none of them are required
+func (s *Section) parseIndex(md []byte) error {
+	doc, err := markdown.ToHTML(md)
+	if err != nil {
+		return err
+	}
+
+	title, ok, err := metadataString(doc.Metadata, "title")
+	if err != nil {
+		return err
+	}
+	if ok {
+		s.Title = title
+	}
+
+	s.Description, _, err = metadataString(doc.Metadata, "description")
+	if err != nil {
+		return err
+	}
+
+	s.Content = template.HTML(doc.Content)
+	return nil
+}
+
+// generateSections renders a listing for every section that doesn't have
+// its own index.md. subsections are listed even if their parent has one
+func generateSections(
+	site *SiteContext,
+	layout *template.Template,
+	sections []*Section,
+	nodes []Node,
+) ([]Node, error) {
+	var generated []Node
+	for _, section := range sections {
+		sub, err := generateSections(site, layout, section.Sections, nodes)
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, sub...)
+
+		if findNode(nodes, strings.TrimPrefix(section.RelPermalink, "/")+"index.html") != nil {
+			continue
+		}
+
+		ctx := Context{Site: site, Page: section.page(), Section: section}
+		list, err := renderList(layout, ctx, section.Pages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", section.Name, err)
+		}
+		generated = append(generated, list...)
+	}
+	return generated, nil
+}
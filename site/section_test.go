@@ -0,0 +1,247 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// sectionTestFiles are the pages of the section tests
+var sectionTestFiles = map[string]string{
+	"content/a.md":              "+++\ntitle = \"top\"\ndate = 2024-01-01\n+++\n",
+	"content/notes/_index.md":   "+++\ntitle = \"My notes\"\n+++\nthings I wrote down",
+	"content/notes/a.md":        "+++\ntitle = \"note a\"\ndate = 2024-01-01\n+++\n",
+	"content/notes/b.md":        "+++\ntitle = \"note b\"\ndate = 2024-02-01\n+++\n",
+	"content/notes/go/c.md":     "+++\ntitle = \"note c\"\ndate = 2024-03-01\n+++\n",
+	"content/projects/index.md": "+++\ntitle = \"projects\"\ndate = 2024-01-01\n+++\nmine",
+	"content/projects/p.md":     "+++\ntitle = \"p\"\ndate = 2024-01-01\n+++\n",
+	"content/images/a.png":      "png",
+}
+
+func TestSections(t *testing.T) {
+	entries := siteEntries("", sectionTestFiles)
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// strings that have to be in the node in this order
+		contains []string
+		missing  []string
+	}{
+		{
+			"content/notes/index.html",
+			[]string{
+				"<title>My notes</title>",
+				"<p>things I wrote down</p>",
+				`<a class="section-item" href="/content/notes/go/">`,
+				`href="/content/notes/b/"`,
+				`href="/content/notes/a/"`,
+			},
+			[]string{"/content/notes/go/c/", "/content/a/"},
+		},
+		{
+			"content/notes/go/index.html",
+			[]string{"<h1>go</h1>", `href="/content/notes/go/c/"`},
+			[]string{"section-item"},
+		},
+		{
+			"content/projects/index.html",
+			[]string{"<title>projects</title>", "<p>mine</p>", `href="/content/projects/p/"`},
+			nil,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			last := -1
+			for _, str := range tt.contains {
+				i := bytes.Index(node.Content, []byte(str))
+				if i <= last {
+					t.Fatalf("%s is missing or in the wrong order in %s\n%s", str, tt.name, node.Content)
+				}
+				last = i
+			}
+			for _, str := range tt.missing {
+				if bytes.Contains(node.Content, []byte(str)) {
+					t.Errorf("%s shouldn't contain %s\n%s", tt.name, str, node.Content)
+				}
+			}
+		})
+	}
+
+	for _, name := range []string{"content/images/index.html", "content/notes/_index/index.html", "content/notes/_index.md"} {
+		if findNode(s.Nodes, name) != nil {
+			t.Errorf("%s shouldn't be generated", name)
+		}
+	}
+	if diagnostics := CheckEntries(entries, BuildOptions{}); diagnostics.HasErrors() {
+		t.Errorf("section links should exist. got %v", diagnostics)
+	}
+}
+
+func TestPaginatedSections(t *testing.T) {
+	s, err := BuildFromEntries(siteEntries("paginate = 1\n", sectionTestFiles), BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := findNode(s.Nodes, "content/notes/page/2/index.html")
+	if page == nil {
+		t.Fatal("content/notes/page/2/index.html wasn't generated")
+	}
+	if !bytes.Contains(page.Content, []byte("note a")) || bytes.Contains(page.Content, []byte("note b")) {
+		t.Errorf("wrong pages on page 2\n%s", page.Content)
+	}
+	// the intro of _index.md is only on the first page
+	if bytes.Contains(page.Content, []byte("things I wrote down")) {
+		t.Errorf("page 2 shouldn't have the intro\n%s", page.Content)
+	}
+}
+
+func TestSectionIndexErrors(t *testing.T) {
+	entries := []Entry{
+		defaultSsgTomlEntry(),
+		defaultThemeDirEntry(),
+		&testEntry{name: "content", typ: DirectoryEntry, children: []Entry{
+			&testEntry{name: "content/notes", typ: DirectoryEntry, children: []Entry{
+				&testEntry{name: "content/notes/_index.md", typ: FileEntry, content: "+++\ntitle = 1\n+++\n"},
+			}},
+		}},
+	}
+	_, err := BuildFromEntries(entries, BuildOptions{})
+	expected := "content/notes/_index.md: error: title has to be a string. got 1"
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong err. expected=%q. got=%q", expected, err)
+	}
+}
+
+func TestIndexPages(t *testing.T) {
+	entries := addFileEntries(siteEntries("paginate = 1\n", sectionTestFiles), map[string]string{
+		"index.md": "+++\ntitle = \"home\"\ndate = 2024-01-01\n+++\nan intro",
+	})
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := findNode(s.Nodes, "index.html")
+	expected := []string{
+		`<link id="theme" rel="stylesheet" href="/themes/dark.css" />`,
+		"<title>home</title>",
+		"<p>an intro</p>",
+		`href="/content/notes/go/c/"`,
+		`<a class="pagination-next" href="/page/2/">`,
+	}
+	last := -1
+	for _, str := range expected {
+		i := bytes.Index(index.Content, []byte(str))
+		if i <= last {
+			t.Fatalf("%s is missing or in the wrong order\n%s", str, index.Content)
+		}
+		last = i
+	}
+
+	page := findNode(s.Nodes, "page/2/index.html")
+	if page == nil {
+		t.Fatal("page/2/index.html wasn't generated")
+	}
+	// the intro is only on the first page
+	if bytes.Contains(page.Content, []byte("<p>an intro</p>")) ||
+		!bytes.Contains(page.Content, []byte(`href="/content/notes/b/"`)) {
+		t.Errorf("wrong second page\n%s", page.Content)
+	}
+
+	// index pages list other pages instead of being listed
+	for i := 2; i <= 6; i++ {
+		name := fmt.Sprintf("page/%d/index.html", i)
+		page := findNode(s.Nodes, name)
+		if page == nil {
+			if i != 6 {
+				t.Errorf("%s wasn't generated", name)
+			}
+			continue
+		}
+		if i == 6 {
+			t.Errorf("there should only be 5 pages")
+		}
+		if bytes.Contains(page.Content, []byte(`href="/content/projects/"`)) {
+			t.Errorf("content/projects/index.md shouldn't be listed")
+		}
+	}
+}
+
+func TestRootSectionIndex(t *testing.T) {
+	entries := addFileEntries(siteEntries("", sectionTestFiles), map[string]string{
+		"content/_index.md": "+++\ntitle = \"Home\"\ndescription = \"all of it\"\n+++\nwelcome",
+		"layouts/index.html": `{{template "base.html" .}}` +
+			`{{define "main"}}<p class="description">{{.Page.Description}}</p>{{.Page.Content}}{{end}}`,
+	})
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := findNode(s.Nodes, "index.html")
+	if index == nil {
+		t.Fatal("index.html wasn't generated")
+	}
+	for _, str := range []string{"<title>Home</title>", `<p class="description">all of it</p>`, "<p>welcome</p>"} {
+		if !bytes.Contains(index.Content, []byte(str)) {
+			t.Errorf("expected index.html to contain %s. got=\n%s", str, index.Content)
+		}
+	}
+}
+
+func TestSlugifiedSections(t *testing.T) {
+	entries := siteEntries("", map[string]string{
+		"content/My Notes/note-one.md": "+++\ntitle = \"note one\"\ndate = 2024-01-01\n+++\n",
+		"content/My Notes/Go Notes/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\n+++\n" +
+			"[up](/content/my-notes/) [one](../../note-one/)",
+	})
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		contains []string
+	}{
+		{
+			"content/my-notes/index.html",
+			[]string{
+				`<a class="section-item" href="/content/my-notes/go-notes/">`,
+				`<a class="blog-item" href="/content/my-notes/note-one/">`,
+			},
+		},
+		{"content/my-notes/go-notes/index.html", []string{`<a class="blog-item" href="/content/my-notes/go-notes/a/">`}},
+		{"content/my-notes/go-notes/a/index.html", nil},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			for _, str := range tt.contains {
+				if !bytes.Contains(node.Content, []byte(str)) {
+					t.Errorf("expected %s to contain %s. got=\n%s", tt.name, str, node.Content)
+				}
+			}
+		})
+	}
+
+	if findNode(s.Nodes, "content/My Notes") != nil {
+		t.Errorf("content/My Notes shouldn't be built once its pages are moved")
+	}
+	if diagnostics := CheckEntries(entries, BuildOptions{}); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics. got %v", diagnostics)
+	}
+}
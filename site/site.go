@@ -1,11 +1,11 @@
 package site
 
 import (
-	"bytes"
-	_ "embed"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -31,18 +31,30 @@ const (
 	FileEntry
 )
 
-const DateLayout = "02-01-2006"
-
 type Node struct {
 	// Name is the file name of the entry this Node is based on.
-	// Use Metadata["title"] to the get the Node's title.
+	// Use Page.Title to the get the Node's title.
 	Name string
-	Type NodeType
-	// nil if Type is DirectoryNode
-	Content  []byte
+	// Source is the path of the entry this Node was built from.
+	// empty for generated nodes.
+	Source string
+	Type   NodeType
+	// nil if Type is DirectoryNode or if the content is read from Path
+	Content []byte
+	// Path is the file on disk that a FileNode's content is streamed from
+	// so that large files don't have to be kept in memory.
+	// empty if the content is in Content
+	Path string
+	// Mode is the file mode of the file at Path
+	Mode     fs.FileMode
 	Children []Node
-	// It is guaranteed that this contains the keys 'title' and 'date'
-	Metadata map[string]string
+	// Page is the metadata of a node built from a markdown file.
+	// nil for every other node
+	Page *Page
+	// Redirect is the url of the page that an alias redirects to. the
+	// development server answers with a 301 instead of the node's content.
+	// empty for every node that isn't an alias
+	Redirect string
 }
 
 func (n Node) String() string {
@@ -73,7 +85,10 @@ type Entry interface {
 }
 
 type directoryEntry struct {
-	name     string
+	name string
+	// path of the entry on disk. unlike name this isn't relative to the site
+	path     string
+	mode     fs.FileMode
 	typ      EntryType
 	content  []byte
 	children []Entry
@@ -94,6 +109,8 @@ func (de *directoryEntry) Type() EntryType {
 	// panic(fmt.Sprint("invalid dirEntry Type:", de.dirEntry.Type()))
 }
 
+// Content is nil for files that aren't parsed by the builder. they're
+// copied from disk when the site is built
 func (de *directoryEntry) Content() []byte {
 	return de.content
 }
@@ -109,13 +126,24 @@ func newDirectoryEntry(
 	deName := filepath.Join(parentName, de.Name())
 
 	if de.Type().IsRegular() {
-		content, err := os.ReadFile(deName)
+		info, err := de.Info()
 		if err != nil {
 			return nil, err
 		}
+
+		var content []byte
+		if isParsed(deName) {
+			content, err = os.ReadFile(deName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		return &directoryEntry{
 			typ:      FileEntry,
 			name:     deName,
+			path:     deName,
+			mode:     info.Mode(),
 			children: nil,
 			content:  content,
 		}, nil
@@ -126,33 +154,50 @@ func newDirectoryEntry(
 		return nil, fmt.Errorf("failed to open directory: %w", err)
 	}
 
-	children := make([]Entry, len(dirEntries))
-	for i, entry := range dirEntries {
-		children[i], err = newDirectoryEntry(entry, deName)
+	children := make([]Entry, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		if isBuildOutput(deName, entry) {
+			continue
+		}
+		child, err := newDirectoryEntry(entry, deName)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"failed to create a directory entry: %w",
 				err,
 			)
 		}
+		children = append(children, child)
 	}
 
 	return &directoryEntry{
 		name:     deName,
+		path:     deName,
 		typ:      DirectoryEntry,
 		children: children,
 		content:  nil,
 	}, nil
 }
 
+// files that are parsed by the builder are read into memory when the site
+// is loaded, everything else is streamed from disk. html files are read
+// since they could be layouts
+func isParsed(name string) bool {
+	ext := filepath.Ext(name)
+	return isMarkdown(name) || ext == ".toml" || ext == ".html"
+}
+
 func loadDirectoryEntries(dir string) ([]Entry, error) {
 	dirContents, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open directory: %w", err)
 	}
 
-	entries := make([]Entry, len(dirContents))
-	for i, entry := range dirContents {
+	entries := make([]Entry, 0, len(dirContents))
+	for _, entry := range dirContents {
+		if isBuildOutput(dir, entry) {
+			continue
+		}
+
 		de, err := newDirectoryEntry(entry, dir)
 		if err != nil {
 			return nil, err
@@ -160,12 +205,17 @@ func loadDirectoryEntries(dir string) ([]Entry, error) {
 
 		stripEntryPrefix(de, dir)
 
-		entries[i] = de
-
+		entries = append(entries, de)
 	}
 	return entries, nil
 }
 
+// a build directory inside of the site (e.g. when running ssg build .)
+// shouldn't become part of the site
+func isBuildOutput(parent string, entry os.DirEntry) bool {
+	return entry.IsDir() && isBuildDirectory(filepath.Join(parent, entry.Name()))
+}
+
 func stripEntryPrefix(de *directoryEntry, prefix string) {
 	de.name = strings.TrimPrefix(de.name, prefix)
 
@@ -189,11 +239,24 @@ func Build(dir string, opts BuildOptions) (Site, error) {
 	if err != nil {
 		return Site{}, err
 	}
-	return BuildFromEntries(entries, opts)
+
+	s, err := BuildFromEntries(entries, opts)
+	if err != nil {
+		return Site{}, err
+	}
+	s.Dir = dir
+	return s, nil
 }
 
 type siteBuilder struct {
-	config SiteConfig
+	config  SiteConfig
+	layouts layouts
+
+	// errors in a file are recorded here and the build continues with the
+	// next file so that every error can be reported at once
+	diagnostics Diagnostics
+	// names of the nodes that would have been built from files with errors
+	failedNodes []string
 }
 
 func newSiteBuilder(entries []Entry, opts BuildOptions) (siteBuilder, error) {
@@ -209,71 +272,103 @@ func newSiteBuilder(entries []Entry, opts BuildOptions) (siteBuilder, error) {
 			}
 			config.BuildDrafts = opts.BuildDrafts
 			config.EnableHotReloading = opts.EnableHotReloading
-			return siteBuilder{config}, nil
+			return siteBuilder{config: config}, nil
 		}
 	}
 	return siteBuilder{}, fmt.Errorf("no ssg.toml file found in project root")
 }
 
-func (sb *siteBuilder) build(entries []Entry) (Site, error) {
-	nodes, err := sb.buildNodes(entries)
+// build records errors in sb.diagnostics instead of returning them.
+// the returned Site only contains the nodes that were built successfully
+func (sb *siteBuilder) build(entries []Entry) Site {
+	nodes := sb.placePages(sb.buildNodes(entries))
+
+	site, pages := newSiteContext(sb.config, nodes)
+
+	var err error
+	site.Menus, err = resolveMenus(sb.config, entries, site, pages)
 	if err != nil {
-		return Site{}, err
+		sb.addError("ssg.toml", err)
 	}
 
-	var contentNode *Node
+	site.Feeds = feedLinks(site)
 
-	indexFound := false
-	for i, node := range nodes {
-		if node.Name == "content" {
-			contentNode = &node
-		}
+	root := sb.buildSections(entries, site)
 
-		if strings.HasSuffix(node.Name, "index.html") {
-			indexFound = true
+	// the other pages of index pages that list more pages than fit on one
+	for _, node := range sb.renderPages(nodes, site, pages, root) {
+		nodes = addNode(nodes, node)
+	}
 
-			// this is really stupid, buildNode formats index.html
-			// using generateBlogHTML and we don't want that for index.html files
-			// so we reset it here. should think of a better way for doing this
-			// maybe only files in content/ get the blog.html template
-			doc, err := markdown.ToHTML(entries[i].Content())
-			if err != nil {
-				return Site{}, fmt.Errorf("markdown.ToHTML failed: %w", err)
-			}
-			nodes[i].Content = doc.Content
+	feeds, err := generateFeeds(site, nodes)
+	if err != nil {
+		sb.addError("", err)
+	}
+	nodes = append(nodes, feeds...)
 
-			break
+	// pages in content/ can be moved out of it by permalinks so the
+	// section is checked instead of the directory
+	if findNode(nodes, "index.html") == nil && root != nil && !root.isEmpty() {
+		index, err := generateIndexNodes(site, sb.layouts.index, root)
+		if err != nil {
+			sb.addError("", fmt.Errorf("error generating index node: %w", err))
+		}
+		for _, node := range index {
+			nodes = addNode(nodes, node)
 		}
 	}
 
-	if !indexFound && contentNode != nil {
-		node, err := generateIndexNode(
-			rootPageInfo{
-				Title:       sb.config.Title,
-				Theme:       sb.config.Theme,
-				ContentNode: *contentNode,
-			},
-		)
+	if root != nil {
+		sections, err := generateSections(site, sb.layouts.section, root.Sections, nodes)
 		if err != nil {
-			return Site{}, fmt.Errorf("error generating index node: %w", err)
+			sb.addError("", err)
 		}
+		for _, node := range sections {
+			nodes = addNode(nodes, node)
+		}
+	}
 
-		nodes = append(nodes, node)
+	taxonomies, err := generateTaxonomies(site, sb.layouts)
+	if err != nil {
+		sb.addError("", err)
+	}
+	for _, node := range taxonomies {
+		nodes = addNode(nodes, node)
 	}
 
+	aliases, err := sb.generateAliases(site, pages, nodes)
+	if err != nil {
+		sb.addError("", err)
+	}
+	for _, node := range aliases {
+		nodes = addNode(nodes, node)
+	}
+
+	sitemap, err := generateSitemapNodes(site, nodes)
+	if err != nil {
+		sb.addError("", err)
+	}
+	nodes = append(nodes, sitemap...)
+
 	return Site{
 		Nodes:  nodes,
 		Config: sb.config,
-	}, nil
+	}
 }
 
-func (sb *siteBuilder) buildNodes(entries []Entry) ([]Node, error) {
+func (sb *siteBuilder) addError(file string, err error) {
+	sb.diagnostics = append(sb.diagnostics, newDiagnostic(file, err))
+}
+
+func (sb *siteBuilder) buildNodes(entries []Entry) []Node {
 	nodes := make([]Node, 0, len(entries))
 
 	for _, entry := range entries {
 		node, err := sb.buildNode(entry)
 		if err != nil {
-			return nil, err
+			sb.addError(entry.Name(), err)
+			sb.failedNodes = append(sb.failedNodes, nodeName(entry.Name()))
+			continue
 		}
 		if node == nil {
 			continue
@@ -282,34 +377,21 @@ func (sb *siteBuilder) buildNodes(entries []Entry) ([]Node, error) {
 		nodes = append(nodes, *node)
 	}
 
-	for i := range nodes {
-		if nodes[i].Type != HTMLNode {
-			continue
-		}
-
-	}
-
-	slices.SortFunc(nodes, func(a, b Node) int {
-		if a.Type != HTMLNode || b.Type != HTMLNode {
-			return 0
-		}
-
-		// TODO: this can be made way better. cache dates
-
-		dA, err := time.Parse(DateLayout, a.Metadata["date"])
-		if err != nil {
-			panic(fmt.Sprintf("unreachable: %s", err))
+	// pages are sorted newest first and come before everything else, which
+	// is sorted by name
+	slices.SortStableFunc(nodes, func(a, b Node) int {
+		switch {
+		case a.Page != nil && b.Page != nil:
+			return b.Page.Date.Compare(a.Page.Date)
+		case a.Page != nil:
+			return -1
+		case b.Page != nil:
+			return 1
 		}
-
-		dB, err := time.Parse(DateLayout, b.Metadata["date"])
-		if err != nil {
-			panic("unreachable")
-		}
-
-		return dB.Compare(dA)
+		return strings.Compare(a.Name, b.Name)
 	})
 
-	return nodes, nil
+	return nodes
 }
 
 func (sb *siteBuilder) buildNode(entry Entry) (*Node, error) {
@@ -317,6 +399,10 @@ func (sb *siteBuilder) buildNode(entry Entry) (*Node, error) {
 	if strings.HasPrefix(entry.Name(), ".") {
 		return nil, nil
 	}
+	// _index.md files are read by buildSections
+	if entry.Type() == FileEntry && isSectionIndex(entry.Name()) {
+		return nil, nil
+	}
 
 	switch entry.Type() {
 	case DirectoryEntry:
@@ -324,218 +410,235 @@ func (sb *siteBuilder) buildNode(entry Entry) (*Node, error) {
 		if len(children) == 0 {
 			return nil, nil
 		}
-		childNodes, err := sb.buildNodes(children)
-		if err != nil {
-			return nil, err
-		}
+		childNodes := sb.buildNodes(children)
 		return &Node{
 			Name:     entry.Name(),
+			Source:   entry.Name(),
 			Type:     DirectoryNode,
 			Content:  nil,
 			Children: childNodes,
 		}, nil
 
 	case FileEntry:
-		const MarkdownExtension = ".md"
-		name := entry.Name()
-		content := entry.Content()
+		name := nodeName(entry.Name())
 		nodeType := FileNode
 
-		var metadata map[string]string
+		// don't keep files that are on disk in memory
+		if de, ok := entry.(*directoryEntry); ok && !isMarkdown(entry.Name()) {
+			return &Node{
+				Name:   name,
+				Source: entry.Name(),
+				Type:   nodeType,
+				Path:   de.path,
+				Mode:   de.mode,
+			}, nil
+		}
+
+		content := entry.Content()
+
+		var page *Page
 
 		// convert all markdown files to html
-		if strings.HasSuffix(name, MarkdownExtension) {
-			extensionIndex := len(name) - len(MarkdownExtension)
-			name = name[:extensionIndex] + ".html"
+		if isMarkdown(entry.Name()) {
 			doc, err := markdown.ToHTML(content)
-			metadata = doc.Metadata
 			if err != nil {
-				return nil, fmt.Errorf("markdown.ToHTML failed: %w", err)
-			}
-
-			if !sb.config.BuildDrafts {
-				draft, err := isDraft(doc)
-				if err != nil {
-					return nil, err
-				}
-				if draft {
-					return nil, nil
-				}
+				return nil, err
 			}
 
-			config := blogConfig{
-				siteTitle:          sb.config.Title,
-				theme:              sb.config.Theme,
-				enableHotReloading: sb.config.EnableHotReloading,
+			page, err = newPage(doc.Metadata, sb.config.Location, entry.Name())
+			if err != nil {
+				return nil, err
 			}
-			content, err = generateBlogHTML(doc, config)
+			err = validateTerms(page, sb.config.taxonomies())
 			if err != nil {
-				return nil, fmt.Errorf(
-					"failed to generate blog html for %s: %w",
-					name,
-					err,
-				)
+				return nil, err
+			}
+
+			if page.Draft && !sb.config.BuildDrafts {
+				return nil, nil
 			}
+			name = pageName(sb.config, page, entry.Name())
+
+			// the page is rendered with its layout by renderPages once
+			// every page is built
+			content = doc.Content
 			nodeType = HTMLNode
 		}
 
 		return &Node{
 			Name:     name,
+			Source:   entry.Name(),
 			Type:     nodeType,
 			Children: nil,
 			Content:  content,
-			Metadata: metadata,
+			Page:     page,
 		}, nil
 	}
 	// unreachable
 	panic(fmt.Sprint("unreachable. invalid entry type:", entry.Type()))
 }
 
-func isDraft(doc markdown.HTMLDoc) (bool, error) {
-	draft, ok := doc.Metadata["draft"]
-	if !ok {
-		return false, nil
-	}
-	switch draft {
-	case "true":
-		return true, nil
-	case "false":
-		return false, nil
-	}
-	return false, fmt.Errorf(
-		"Invalid value for draft %s. expected true or false",
-		draft,
-	)
+// IsPublished reports whether node is part of the built site.
+// files like ssg.toml are only used to build the site
+func IsPublished(node Node) bool {
+	return node.Name != "ssg.toml" && !isLayoutsDir(node.Name)
 }
 
-//go:embed templates/blog.html
-var blogRes string
-var blogTmpl = template.Must(template.New("blog").Parse(blogRes))
+const markdownExtension = ".md"
 
-type blogConfig struct {
-	siteTitle          string
-	theme              string
-	enableHotReloading bool
+func isMarkdown(name string) bool {
+	return strings.HasSuffix(name, markdownExtension)
 }
 
-func generateBlogHTML(
-	doc markdown.HTMLDoc,
-	config blogConfig,
-) ([]byte, error) {
-	type blogTemplate struct {
-		SiteTitle          string
-		Title              string
-		AuthorName         string
-		Theme              string
-		PublishedDate      string
-		Blog               template.HTML
-		EnableHotReloading bool
+// nodeName returns the name of the node built from the entry called name.
+// markdown files are converted to html and get pretty urls. e.g.
+// content/My Notes/My Post.md is built into content/my-notes/my-post/index.html.
+// pageName also takes the front matter and ssg.toml into account
+func nodeName(name string) string {
+	if !isMarkdown(name) {
+		return name
 	}
-
-	author, _ := doc.Metadata["author"]
-
-	dateString, ok := doc.Metadata["date"]
-	if !ok {
-		return nil, fmt.Errorf("blog date not found")
-	}
-	if err := isValidDate(dateString); err != nil {
-		return nil, err
+	if isIndexPage(name) {
+		return path.Join(dirSlug(path.Dir(name)), "index.html")
 	}
+	return path.Join(dirSlug(path.Dir(name)), fileSlug(name), "index.html")
+}
 
-	title, ok := doc.Metadata["title"]
-	if !ok {
-		return nil, fmt.Errorf("blog title not found")
-	}
+// renderPages renders every page in nodes with the blog layout. index pages
+// are rendered with the layout of the list they're in. e.g. index.md is
+// rendered with index.html and gets the pages in content/. nodes are changed
+// in place and the other pages of paginated index pages are returned
+func (sb *siteBuilder) renderPages(
+	nodes []Node,
+	site *SiteContext,
+	pages map[string]*PageContext,
+	root *Section,
+) []Node {
+	var pagers []Node
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Type == DirectoryNode {
+			pagers = append(pagers, sb.renderPages(node.Children, site, pages, root)...)
+			continue
+		}
 
-	blogInfo := blogTemplate{
-		SiteTitle:          config.siteTitle,
-		Title:              title,
-		Theme:              config.theme,
-		EnableHotReloading: config.enableHotReloading,
-		AuthorName:         author,
-		PublishedDate:      dateString,
-		Blog:               template.HTML(doc.Content),
-	}
+		page, ok := pages[node.Source]
+		if !ok {
+			continue
+		}
 
-	var buf bytes.Buffer
-	err := blogTmpl.Execute(&buf, blogInfo)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute blog template: %w", err)
-	}
+		layout, ctx, list, ok := sb.indexList(node.Source, site, root)
+		if !ok {
+			content, err := renderLayout(sb.layouts.blog, Context{Site: site, Page: page})
+			if err != nil {
+				sb.addError(node.Source, err)
+				continue
+			}
+			node.Content = content
+			continue
+		}
 
-	return buf.Bytes(), nil
+		ctx.Page = page
+		rendered, err := renderList(layout, ctx, list)
+		if err != nil {
+			sb.addError(node.Source, err)
+			continue
+		}
+		node.Content = rendered[0].Content
+		pagers = append(pagers, rendered[1:]...)
+	}
+	return pagers
 }
 
-//go:embed templates/index.html
-var indexRes string
-var indexTmpl = template.Must(template.New("index").Parse(indexRes))
-
-type rootPageInfo struct {
-	Title       string
-	ContentNode Node
-	Theme       string
+// indexList returns the layout, context and pages of the list that the
+// page built from file is the first page of. false if file isn't an index
+// page of a list. index.md lists the pages in content/ and the index.md
+// files of the directories in content/ list the pages in them. root is nil
+// if the site doesn't have a content/
+func (sb *siteBuilder) indexList(
+	file string,
+	site *SiteContext,
+	root *Section,
+) (*template.Template, Context, []*PageContext, bool) {
+	if file == "index.md" {
+		return sb.layouts.index, Context{Site: site, Section: root}, site.Sections["content"], true
+	}
+	if !isIndexPage(file) || root == nil {
+		return nil, Context{}, nil, false
+	}
+	section := root.find(path.Dir(file))
+	if section == nil {
+		return nil, Context{}, nil, false
+	}
+	return sb.layouts.section, Context{Site: site, Section: section}, section.Pages, true
 }
 
-func isValidDate(s string) error {
-	_, err := time.Parse(DateLayout, s)
-	return err
+// generateIndexNodes lists the pages in content/ and its subdirectories.
+// index.html is the first page of the list and the rest are in page/
+func generateIndexNodes(
+	site *SiteContext,
+	layout *template.Template,
+	content *Section,
+) ([]Node, error) {
+	ctx := Context{Site: site, Page: content.page(), Section: content}
+	return renderList(layout, ctx, site.Sections["content"])
 }
 
-func generateIndexNode(rpi rootPageInfo) (Node, error) {
-	type BlogItem struct {
-		Title string
-		Link  string
-		Date  string
-	}
-
-	type TemplateData struct {
-		SiteTitle string
-		Theme     string
-		Blogs     []BlogItem
+// BuildFromEntries builds every entry before returning.
+// if there are any errors the returned error is of type Diagnostics and
+// contains all of them
+func BuildFromEntries(entries []Entry, opts BuildOptions) (Site, error) {
+	s, sb := buildWithDiagnostics(entries, opts)
+	if sb.diagnostics.HasErrors() {
+		return Site{}, sb.diagnostics
 	}
+	return s, nil
+}
 
-	blogItems := make([]BlogItem, len(rpi.ContentNode.Children))
-
-	for i, node := range rpi.ContentNode.Children {
-		date := node.Metadata["date"]
-		title := node.Metadata["title"]
-		blogItems[i] = BlogItem{
-			Title: title,
-			Link:  "/" + node.Name,
-			Date:  date,
+// returns the builder so that its diagnostics can be inspected
+func buildWithDiagnostics(
+	entries []Entry,
+	opts BuildOptions,
+) (Site, siteBuilder) {
+	sb, err := newSiteBuilder(entries, opts)
+	if err != nil {
+		file := ""
+		if hasEntry(entries, "ssg.toml") {
+			file = "ssg.toml"
 		}
+		// keep going with an empty config so that the content is still checked
+		sb = siteBuilder{
+			config: SiteConfig{
+				BuildDrafts:        opts.BuildDrafts,
+				EnableHotReloading: opts.EnableHotReloading,
+			},
+		}
+		sb.addError(file, err)
 	}
 
-	tmplData := TemplateData{
-		SiteTitle: rpi.Title,
-		Theme:     rpi.Theme,
-		Blogs:     blogItems,
-	}
-
-	var html bytes.Buffer
-	err := indexTmpl.Execute(&html, tmplData)
+	sb.layouts, err = loadLayouts(entries, sb.config)
 	if err != nil {
-		return Node{}, err
+		// pages are still built with the default layouts to check them
+		sb.layouts = defaultLayouts
+		sb.addError("", err)
 	}
 
-	return Node{
-		Name:     "index.html",
-		Type:     HTMLNode,
-		Content:  html.Bytes(),
-		Children: nil,
-	}, nil
+	s := sb.build(entries)
+	return s, sb
 }
 
-func BuildFromEntries(entries []Entry, opts BuildOptions) (Site, error) {
-	sb, err := newSiteBuilder(entries, opts)
-	if err != nil {
-		return Site{}, fmt.Errorf("failed to build site: %w", err)
+func hasEntry(entries []Entry, name string) bool {
+	for _, entry := range entries {
+		if entry.Name() == name {
+			return true
+		}
 	}
-	return sb.build(entries)
+	return false
 }
 
 func parseConfig(entries []Entry, ssgToml []byte) (SiteConfig, error) {
-	config, err := toml.Parse(ssgToml)
+	var config SiteConfig
+	err := toml.Unmarshal(ssgToml, &config)
 	if err != nil {
 		return SiteConfig{}, fmt.Errorf(
 			"failed to parse ssg.toml file: %w",
@@ -543,31 +646,46 @@ func parseConfig(entries []Entry, ssgToml []byte) (SiteConfig, error) {
 		)
 	}
 
-	title, ok := config["title"]
-	if !ok {
+	switch {
+	case config.Title == "":
 		return SiteConfig{}, fmt.Errorf("no title provided in ssg.toml")
+	case config.Author == "":
+		return SiteConfig{}, fmt.Errorf("no author provided in ssg.toml")
+	case config.Theme == "":
+		return SiteConfig{}, fmt.Errorf("no theme provided in ssg.toml")
 	}
 
-	author, ok := config["author"]
-	if !ok {
-		return SiteConfig{}, fmt.Errorf("no author provided in ssg.toml")
+	themeName, err := findThemeName(entries, config.Theme)
+	if err != nil {
+		return SiteConfig{}, err
 	}
+	config.Theme = "/" + themeName
 
-	theme, ok := config["theme"]
-	if !ok {
-		return SiteConfig{}, fmt.Errorf("no theme provided in ssg.toml")
+	config.Location, err = time.LoadLocation(config.Timezone)
+	if err != nil {
+		return SiteConfig{}, fmt.Errorf("invalid timezone %s: %w", config.Timezone, err)
 	}
 
-	themeName, err := findThemeName(entries, theme)
+	err = validateMenus(config)
 	if err != nil {
 		return SiteConfig{}, err
 	}
 
-	return SiteConfig{
-		Author: author,
-		Title:  title,
-		Theme:  "/" + themeName,
-	}, nil
+	if config.Paginate < 0 {
+		return SiteConfig{}, fmt.Errorf("paginate can't be negative. got %d", config.Paginate)
+	}
+
+	err = validateTaxonomies(config)
+	if err != nil {
+		return SiteConfig{}, err
+	}
+
+	err = validatePermalinks(config)
+	if err != nil {
+		return SiteConfig{}, err
+	}
+
+	return config, nil
 }
 
 func findThemeName(entries []Entry, theme string) (string, error) {
@@ -590,15 +708,48 @@ func findThemeName(entries []Entry, theme string) (string, error) {
 	return "", fmt.Errorf("theme %s not found in themes/", theme)
 }
 
+// SiteConfig is decoded from ssg.toml
 type SiteConfig struct {
-	Author             string
-	Title              string
-	Theme              string
-	BuildDrafts        bool
-	EnableHotReloading bool
+	Author string `toml:"author"`
+	Title  string `toml:"title"`
+	// the name of the theme in ssg.toml. the path of the theme's
+	// stylesheet once the config is parsed
+	Theme string `toml:"theme"`
+	// BaseURL is the URL the site is deployed to. e.g. https://example.com.
+	// permalinks are relative to the root of the site if it's empty
+	BaseURL string `toml:"base_url,omitempty"`
+	// Timezone is the IANA name of the time zone of dates that don't
+	// have one. e.g. Europe/Berlin
+	Timezone string `toml:"timezone" default:"UTC"`
+	// DateFormat is the Go layout that dates are displayed with.
+	// e.g. "January 2, 2006"
+	DateFormat string `toml:"date_format" default:"02-01-2006"`
+	// Menus maps the name of a menu like main to its entries
+	Menus map[string][]MenuEntry `toml:"menu,omitempty"`
+	Feed  FeedConfig             `toml:"feed"`
+	// Redirects turns on files that redirect the aliases of pages on
+	// the server
+	Redirects RedirectsConfig `toml:"redirects,omitempty"`
+	// Paginate is the number of pages on each page of a list like
+	// index.html. every page is on one page if it's 0
+	Paginate int `toml:"paginate,omitempty"`
+	// Taxonomies are the front matter keys that pages are grouped by.
+	// defaults to tags and categories
+	Taxonomies []string `toml:"taxonomies,omitempty"`
+	// Permalinks maps a top level directory like content to the url
+	// pattern of its pages. e.g. /:year/:month/:slug/
+	Permalinks map[string]string `toml:"permalinks,omitempty"`
+	// Social is the list of links in the footer
+	Social             []SocialLink   `toml:"social,omitempty"`
+	Location           *time.Location `toml:"-"`
+	BuildDrafts        bool           `toml:"-"`
+	EnableHotReloading bool           `toml:"-"`
 }
 
 type Site struct {
 	Nodes  []Node
 	Config SiteConfig
+	// Dir is the directory the site was loaded from.
+	// empty if the site was built with BuildFromEntries
+	Dir string
 }
@@ -3,9 +3,16 @@ package site
 import (
 	"bytes"
 	"fmt"
+	"html/template"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Hassan-Ibrahim-1/go-ssg/markdown"
 )
@@ -33,6 +40,49 @@ func (te *testEntry) Children() []Entry {
 	return te.children
 }
 
+// baseURLConfig sets base_url in ssg.toml. feeds need it
+const baseURLConfig = "base_url = \"https://example.com\"\n"
+
+// siteEntries returns the entries of a site with the default ssg.toml and
+// theme. config is added to ssg.toml and files maps paths like
+// content/a.md to their content
+func siteEntries(config string, files map[string]string) []Entry {
+	return addFileEntries([]Entry{
+		&testEntry{name: "ssg.toml", typ: FileEntry, content: defaultSsgToml() + config},
+		defaultThemeDirEntry(),
+	}, files)
+}
+
+// addFileEntries adds files, which maps paths to their content, to entries
+func addFileEntries(entries []Entry, files map[string]string) []Entry {
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		entries = addFileEntry(entries, "", name, files[name])
+	}
+	return entries
+}
+
+// addFileEntry adds the file called name to the entries of the directory
+// dir. the directories it's in are created if they don't exist
+func addFileEntry(entries []Entry, dir, name, content string) []Entry {
+	first, _, nested := strings.Cut(strings.TrimPrefix(name, dir+"/"), "/")
+	if !nested {
+		return append(entries, &testEntry{name: name, typ: FileEntry, content: content})
+	}
+
+	sub := path.Join(dir, first)
+	for _, entry := range entries {
+		if te := entry.(*testEntry); te.name == sub {
+			te.children = addFileEntry(te.children, sub, name, content)
+			return entries
+		}
+	}
+	return append(entries, &testEntry{
+		name:     sub,
+		typ:      DirectoryEntry,
+		children: addFileEntry(nil, sub, name, content),
+	})
+}
+
 func defaultSsgTomlEntry() Entry {
 	return &testEntry{
 		name:     "ssg.toml",
@@ -50,6 +100,18 @@ theme = "dark"
 `
 }
 
+func defaultSiteConfig() SiteConfig {
+	return SiteConfig{
+		Author:     "test author",
+		Title:      "test blog",
+		Theme:      "/themes/dark.css",
+		Timezone:   "UTC",
+		DateFormat: DateLayout,
+		Feed:       FeedConfig{Enabled: true, Limit: 20},
+		Location:   time.UTC,
+	}
+}
+
 func defaultSsgTomlNode() Node {
 	return Node{
 		Name:     "ssg.toml",
@@ -183,7 +245,14 @@ func TestLoadDirectoryEntries(t *testing.T) {
 			typ:     FileEntry,
 			content: defaultSsgToml(),
 		},
-		defaultThemeDirEntry(),
+		// files that aren't parsed are copied from disk when the site is built
+		&testEntry{
+			name: "themes",
+			typ:  DirectoryEntry,
+			children: []Entry{
+				&testEntry{name: "themes/dark.css", typ: FileEntry},
+			},
+		},
 	}
 
 	entries, err := loadDirectoryEntries(testDir.name)
@@ -192,6 +261,15 @@ func TestLoadDirectoryEntries(t *testing.T) {
 	}
 
 	_ = testEntriesEqual(t, entries, expectedEntries)
+
+	// parsed files are read when they're loaded
+	err = os.Remove(testDir.outerFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := entries[2].Content(); string(content) != outerContent {
+		t.Errorf("wrong content. expected=%q. got=%q", outerContent, content)
+	}
 }
 
 type testDirectory struct {
@@ -210,7 +288,7 @@ func TestNewSiteBuilder(t *testing.T) {
 		{
 			[]Entry{
 				defaultSsgTomlEntry(), defaultThemeDirEntry(),
-			}, SiteConfig{"test author", "test blog", "/themes/dark.css", false, false}, nil,
+			}, defaultSiteConfig(), nil,
 		},
 		{
 			[]Entry{
@@ -235,7 +313,7 @@ func TestNewSiteBuilder(t *testing.T) {
 				t.Fatalf("wrong err. expected=%q. got=%q", tt.expectedErr, err)
 			}
 
-			if sb.config != tt.expectedConfig {
+			if !reflect.DeepEqual(sb.config, tt.expectedConfig) {
 				t.Errorf(
 					"wrong config. expected=%v. got=%v",
 					tt.expectedConfig,
@@ -255,6 +333,7 @@ func TestIsDraft(t *testing.T) {
 		{`
 +++
 title=test
+date=01-01-2000
 draft=true
 +++
 `,
@@ -263,6 +342,7 @@ draft=true
 		{`
 +++
 title=test
+date=01-01-2000
 +++
 `,
 			false, nil,
@@ -270,6 +350,7 @@ title=test
 		{`
 +++
 title=test
+date=01-01-2000
 draft = false
 +++
 `,
@@ -278,6 +359,7 @@ draft = false
 		{`
 +++
 title=test
+date=01-01-2000
 draft = 420
 +++
 `,
@@ -286,6 +368,7 @@ draft = 420
 		{`
 +++
 title=test
+date=01-01-2000
 draft = !false
 +++
 `,
@@ -294,11 +377,40 @@ draft = !false
 		{`
 +++
 title=test
+date=01-01-2000
 draft = !true
 +++
 `,
 			false, fmt.Errorf("Invalid value for draft !true. expected true or false"),
 		},
+		{`
++++
+title = "test"
+date = 2000-01-01
+draft = true
++++
+`,
+			true, nil,
+		},
+		{`
+---
+title: test
+date: 2000-01-01
+draft: yes
+---
+`,
+			false, fmt.Errorf("Invalid value for draft yes. expected true or false"),
+		},
+		{`
+---
+title: test
+date: 2000-01-01
+draft: true
+---
+`,
+			true, nil,
+		},
+		{`{"title": "test", "date": "01-01-2000", "draft": false}`, false, nil},
 	}
 
 	for i, tt := range tests {
@@ -307,11 +419,14 @@ draft = !true
 			if err != nil {
 				t.Fatalf("markdown.ToHTMl failed: %v", err)
 			}
-			b, err := isDraft(doc)
+			page, err := newPage(doc.Metadata, time.UTC, "content/post.md")
 			if !errEqual(err, tt.expectedErr) {
 				t.Fatalf("wrong err. expected=%v got=%v", tt.expectedErr, err)
 			}
-			if b != tt.expected {
+			if err != nil {
+				return
+			}
+			if b := page.Draft; b != tt.expected {
 				t.Errorf(
 					"wrong isDraft value. expected=%v got=%v",
 					tt.expected,
@@ -330,40 +445,82 @@ func mdToHTML(t *testing.T, md string) markdown.HTMLDoc {
 	return doc
 }
 
+func docPage(t *testing.T, doc markdown.HTMLDoc) *Page {
+	page, err := newPage(doc.Metadata, time.UTC, "content/post.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+// blogHTML renders doc with the default blog layout of the test site
+func blogHTML(t *testing.T, doc markdown.HTMLDoc) []byte {
+	ctx := Context{
+		Site: &SiteContext{
+			Title:       "test blog",
+			Theme:       "/themes/dark.css",
+			DateFormat:  DateLayout,
+			Environment: production,
+		},
+		Page: &PageContext{
+			Page:    docPage(t, doc),
+			Content: template.HTML(doc.Content),
+		},
+	}
+	html, err := renderLayout(defaultLayouts.blog, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return html
+}
+
+// indexPageHTML renders doc with the default index layout of the test site.
+// pages are the pages it lists
+func indexPageHTML(t *testing.T, doc markdown.HTMLDoc, pages ...*PageContext) []byte {
+	ctx := Context{
+		Site: &SiteContext{
+			Title:       "test blog",
+			Theme:       "/themes/dark.css",
+			DateFormat:  DateLayout,
+			Environment: production,
+		},
+		Page: &PageContext{
+			Page:    docPage(t, doc),
+			Content: template.HTML(doc.Content),
+		},
+		Pages: pages,
+	}
+	html, err := renderLayout(defaultLayouts.index, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return html
+}
+
 func TestBuildDrafts(t *testing.T) {
-	indexMarkdown := "+++\ntitle = Index\n+++\nindex"
-	indexHTML := mdToHTML(t, indexMarkdown).Content
+	indexMarkdown := "+++\ntitle = Index\ndate = 01-01-2000\n+++\nindex"
+	indexHTML := indexPageHTML(t, mdToHTML(t, indexMarkdown))
 
 	draftMarkdown := `
 +++
 title = "some blog"
+date = 2000-01-01
 draft = true
 +++
 hello
 `
 	draftDoc := mdToHTML(t, draftMarkdown)
-	draftHTML, err := generateBlogHTML(
-		draftDoc,
-		blogConfig{siteTitle: "test blog", theme: "/themes/dark.css"},
-	)
-	if err != nil {
-		t.Fatal(err)
-	}
+	draftHTML := blogHTML(t, draftDoc)
 
 	nonDraftMarkdown := `
 +++
 title = "some blog"
+date = 2000-01-01
 +++
 hello
 `
 	nonDraftDoc := mdToHTML(t, nonDraftMarkdown)
-	nonDraftHTML, err := generateBlogHTML(
-		nonDraftDoc,
-		blogConfig{siteTitle: "test blog", theme: "/themes/dark.css"},
-	)
-	if err != nil {
-		t.Fatal(err)
-	}
+	nonDraftHTML := blogHTML(t, nonDraftDoc)
 
 	tests := []struct {
 		buildDrafts bool
@@ -392,22 +549,34 @@ hello
 				},
 			},
 			[]Node{
-				defaultSsgTomlNode(),
-				defaultThemeDirNode(),
 				{
 					Name:    "index.html",
 					Type:    HTMLNode,
 					Content: indexHTML,
 				},
+				defaultSsgTomlNode(),
+				defaultThemeDirNode(),
 				{
-					Name:    "draft.html",
-					Type:    HTMLNode,
-					Content: draftHTML,
+					Name: "draft",
+					Type: DirectoryNode,
+					Children: []Node{
+						{
+							Name:    "draft/index.html",
+							Type:    HTMLNode,
+							Content: draftHTML,
+						},
+					},
 				},
 				{
-					Name:    "nondraft.html",
-					Type:    HTMLNode,
-					Content: nonDraftHTML,
+					Name: "nondraft",
+					Type: DirectoryNode,
+					Children: []Node{
+						{
+							Name:    "nondraft/index.html",
+							Type:    HTMLNode,
+							Content: nonDraftHTML,
+						},
+					},
 				},
 			},
 		},
@@ -433,17 +602,23 @@ hello
 				},
 			},
 			[]Node{
-				defaultSsgTomlNode(),
-				defaultThemeDirNode(),
 				{
 					Name:    "index.html",
 					Type:    HTMLNode,
 					Content: indexHTML,
 				},
+				defaultSsgTomlNode(),
+				defaultThemeDirNode(),
 				{
-					Name:    "nondraft.html",
-					Type:    HTMLNode,
-					Content: nonDraftHTML,
+					Name: "nondraft",
+					Type: DirectoryNode,
+					Children: []Node{
+						{
+							Name:    "nondraft/index.html",
+							Type:    HTMLNode,
+							Content: nonDraftHTML,
+						},
+					},
 				},
 			},
 		},
@@ -473,13 +648,7 @@ func TestParseConfig(t *testing.T) {
 		{
 			[]Entry{defaultThemeDirEntry()},
 			defaultSsgToml(),
-			SiteConfig{
-				"test author",
-				"test blog",
-				"/themes/dark.css",
-				false,
-				false,
-			},
+			defaultSiteConfig(),
 			nil,
 		},
 		{
@@ -529,6 +698,28 @@ theme = "dark"
 			SiteConfig{},
 			fmt.Errorf("no themes/ directory in project root"),
 		},
+		{
+			[]Entry{defaultThemeDirEntry()},
+			`
+# comments and other options are allowed
+title = "test blog"
+author = "test author"
+theme = "dark"
+unused = [1, 2]
+`,
+			defaultSiteConfig(),
+			nil,
+		},
+		{
+			[]Entry{defaultThemeDirEntry()},
+			`
+title = 1
+author = "test author"
+theme = "dark"
+`,
+			SiteConfig{},
+			fmt.Errorf("failed to parse ssg.toml file: line 2: title: expected string, found integer"),
+		},
 	}
 
 	for i, tt := range tests {
@@ -538,7 +729,7 @@ theme = "dark"
 				t.Fatalf("wrong err. expected=%q. got=%q", tt.expectedErr, err)
 			}
 
-			if config != tt.expectedConfig {
+			if !reflect.DeepEqual(config, tt.expectedConfig) {
 				t.Errorf(
 					"wrong config. expected=%v. got=%v",
 					tt.expectedConfig,
@@ -549,13 +740,56 @@ theme = "dark"
 	}
 }
 
+func TestParseConfigDates(t *testing.T) {
+	tests := []struct {
+		ssgToml    string
+		timezone   string
+		dateFormat string
+		err        error
+	}{
+		{defaultSsgToml(), "UTC", DateLayout, nil},
+		{
+			defaultSsgToml() + "timezone = \"Europe/Berlin\"\ndate_format = \"January 2, 2006\"\n",
+			"Europe/Berlin", "January 2, 2006", nil,
+		},
+		{
+			defaultSsgToml() + "timezone = \"Mars/Olympus\"\n",
+			"", "",
+			fmt.Errorf("invalid timezone Mars/Olympus: unknown time zone Mars/Olympus"),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			config, err := parseConfig(
+				[]Entry{defaultThemeDirEntry()},
+				[]byte(tt.ssgToml),
+			)
+			if !errEqual(err, tt.err) {
+				t.Fatalf("wrong err. expected=%q. got=%q", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if config.Location.String() != tt.timezone {
+				t.Errorf("wrong location. expected=%s. got=%s", tt.timezone, config.Location)
+			}
+			if config.DateFormat != tt.dateFormat {
+				t.Errorf("wrong date format. expected=%q. got=%q", tt.dateFormat, config.DateFormat)
+			}
+		})
+	}
+}
+
 func TestBuildFromEntries(t *testing.T) {
-	indexMarkdown := "+++\ntitle = Index\n+++\nindex"
-	indexHTML := mdToHTML(t, indexMarkdown).Content
+	indexMarkdown := "+++\ntitle = Index\ndate = 01-01-2000\n+++\nindex"
+	indexHTML := indexPageHTML(t, mdToHTML(t, indexMarkdown))
 
 	innerMarkdown := `
 +++
 title = "some blog"
+date = 2000-01-01
 +++
 hello
 `
@@ -564,13 +798,13 @@ hello
 		t.Fatal(err)
 	}
 
-	innerHTML, err := generateBlogHTML(
-		innerContentDoc,
-		blogConfig{siteTitle: "test blog", theme: "/themes/dark.css"},
+	innerHTML := blogHTML(t, innerContentDoc)
+	// index.md lists the pages in content/
+	indexWithInnerHTML := indexPageHTML(
+		t,
+		mdToHTML(t, indexMarkdown),
+		&PageContext{Page: docPage(t, innerContentDoc), RelPermalink: "/content/inner/"},
 	)
-	if err != nil {
-		t.Fatal(err)
-	}
 
 	tests := []struct {
 		entries  []Entry
@@ -587,13 +821,13 @@ hello
 				},
 			},
 			[]Node{
-				defaultSsgTomlNode(),
-				defaultThemeDirNode(),
 				{
 					Name:    "index.html",
 					Type:    HTMLNode,
 					Content: indexHTML,
 				},
+				defaultSsgTomlNode(),
+				defaultThemeDirNode(),
 			},
 		},
 		{
@@ -612,8 +846,6 @@ hello
 				},
 			},
 			[]Node{
-				defaultSsgTomlNode(),
-				defaultThemeDirNode(),
 				{
 					Name:    "index.html",
 					Type:    HTMLNode,
@@ -624,6 +856,8 @@ hello
 					Type:    FileNode,
 					Content: []byte("blah blah"),
 				},
+				defaultSsgTomlNode(),
+				defaultThemeDirNode(),
 			},
 		},
 		{
@@ -648,21 +882,27 @@ hello
 				},
 			},
 			[]Node{
-				defaultSsgTomlNode(),
-				defaultThemeDirNode(),
 				{
 					Name:    "index.html",
 					Type:    HTMLNode,
-					Content: indexHTML,
+					Content: indexWithInnerHTML,
 				},
+				defaultSsgTomlNode(),
+				defaultThemeDirNode(),
 				{
 					Name: "content",
 					Type: DirectoryNode,
 					Children: []Node{
 						{
-							Name:    "content/inner.html",
-							Type:    HTMLNode,
-							Content: innerHTML,
+							Name: "content/inner",
+							Type: DirectoryNode,
+							Children: []Node{
+								{
+									Name:    "content/inner/index.html",
+									Type:    HTMLNode,
+									Content: innerHTML,
+								},
+							},
 						},
 					},
 				},
@@ -713,7 +953,7 @@ hello
                 // Parse the string into a Document
                 const doc = parser.parseFromString(event.data, "text/html");
 
-                const newTheme = document.getElementById("theme");
+                const newTheme = doc.getElementById("theme");
                 const theme = document.getElementById("theme");
 
                 const updatedTheme = theme.cloneNode();
@@ -757,9 +997,17 @@ hello
 				t.Fatal(err)
 			}
 
-			html, err := generateBlogHTML(doc, blogConfig{theme: tt.theme})
+			page, err := newPage(doc.Metadata, time.UTC, "content/post.md")
 			if err != nil {
-				t.Fatalf("generateBlogHTML failed: %v", err)
+				t.Fatal(err)
+			}
+
+			html, err := renderLayout(defaultLayouts.blog, Context{
+				Site: &SiteContext{Theme: tt.theme},
+				Page: &PageContext{Page: page, Content: template.HTML(doc.Content)},
+			})
+			if err != nil {
+				t.Fatalf("renderLayout failed: %v", err)
 			}
 
 			htmlLines := bytes.Split(html, []byte{'\n'})
@@ -791,31 +1039,6 @@ hello
 	}
 }
 
-func TestIsValidDate(t *testing.T) {
-	tests := []struct {
-		date        string
-		expectError bool
-	}{
-		{"01-01-2000", false},
-		{"2000-01-01", true},
-	}
-
-	for i, tt := range tests {
-		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
-			err := isValidDate(tt.date)
-			hadError := err != nil
-			if hadError != tt.expectError {
-				t.Errorf(
-					"expected=%v got=%v err=%s",
-					tt.expectError,
-					hadError,
-					err,
-				)
-			}
-		})
-	}
-}
-
 func errEqual(err1, err2 error) bool {
 	if err1 == nil && err2 == nil {
 		return true
@@ -0,0 +1,108 @@
+package site
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	sitemapName = "sitemap.xml"
+	robotsName  = "robots.txt"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	Lastmod string `xml:"lastmod,omitempty"`
+}
+
+// sitemapURLs returns an entry for every html page in nodes. drafts, aliases
+// and pages that set sitemap = false are left out. list pages like index.html
+// change whenever a page does so they get the newest lastmod of the site
+func sitemapURLs(site *SiteContext, nodes []Node) []sitemapURL {
+	var urls []sitemapURL
+	for _, node := range nodes {
+		if node.Type == DirectoryNode {
+			urls = append(urls, sitemapURLs(site, node.Children)...)
+			continue
+		}
+		if node.Type != HTMLNode || !IsPublished(node) || node.Redirect != "" {
+			continue
+		}
+
+		lastmod := feedUpdated(site.Pages)
+		if node.Page != nil {
+			if node.Page.Draft || !node.Page.Sitemap {
+				continue
+			}
+			lastmod = node.Page.Lastmod
+		}
+
+		url := sitemapURL{Loc: absURL(site.BaseURL, strings.TrimSuffix(node.Name, "index.html"))}
+		if !lastmod.IsZero() {
+			url.Lastmod = lastmod.Format(time.RFC3339)
+		}
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+func generateSitemap(site *SiteContext, nodes []Node) ([]byte, error) {
+	urls := sitemapURLs(site, nodes)
+	// nodes are sorted by date, the sitemap is easier to read by url
+	slices.SortStableFunc(urls, func(a, b sitemapURL) int {
+		return cmp.Compare(a.Loc, b.Loc)
+	})
+
+	out, err := xml.MarshalIndent(sitemapURLSet{URLs: urls}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sitemap: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+func generateRobots(site *SiteContext) []byte {
+	return []byte(fmt.Sprintf(
+		"User-agent: *\nAllow: /\n\nSitemap: %s\n",
+		absURL(site.BaseURL, sitemapName),
+	))
+}
+
+// generateSitemapNodes returns sitemap.xml and a robots.txt that points
+// to it. they need absolute links so they're only generated if base_url is
+// set. a site that has its own robots.txt at its root or in static/ keeps it
+func generateSitemapNodes(site *SiteContext, nodes []Node) ([]Node, error) {
+	if site.BaseURL == "" {
+		return nil, nil
+	}
+
+	var generated []Node
+	if findNode(nodes, sitemapName) == nil {
+		content, err := generateSitemap(site, nodes)
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, Node{
+			Name:    sitemapName,
+			Type:    FileNode,
+			Content: content,
+		})
+	}
+
+	if findNode(nodes, robotsName) == nil && findNode(nodes, "static/"+robotsName) == nil {
+		generated = append(generated, Node{
+			Name:    robotsName,
+			Type:    FileNode,
+			Content: generateRobots(site),
+		})
+	}
+	return generated, nil
+}
@@ -0,0 +1,114 @@
+package site
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSitemap(t *testing.T) {
+	hidden := map[string]string{
+		"hidden.md": "+++\ntitle = \"hidden\"\ndate = 2024-06-01\nsitemap = false\n+++\nhidden",
+	}
+	for _, buildDrafts := range []bool{false, true} {
+		s, err := BuildFromEntries(
+			addFileEntries(siteEntries(baseURLConfig, feedTestFiles), hidden),
+			BuildOptions{BuildDrafts: buildDrafts},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sitemap := findNode(s.Nodes, "sitemap.xml")
+		if sitemap == nil {
+			t.Fatal("sitemap.xml wasn't generated")
+		}
+
+		expected := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/</loc>
+    <lastmod>2024-06-01T00:00:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/about/</loc>
+    <lastmod>2024-05-01T00:00:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/content/a/</loc>
+    <lastmod>2024-01-01T00:00:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/content/b/</loc>
+    <lastmod>2024-03-01T00:00:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/tags/</loc>
+    <lastmod>2024-06-01T00:00:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/tags/go/</loc>
+    <lastmod>2024-06-01T00:00:00Z</lastmod>
+  </url>
+</urlset>
+`
+		if string(sitemap.Content) != expected {
+			t.Errorf(
+				"wrong sitemap with drafts=%t.\nexpected=\n%s\ngot=\n%s",
+				buildDrafts,
+				expected,
+				sitemap.Content,
+			)
+		}
+	}
+}
+
+func TestRobots(t *testing.T) {
+	tests := []struct {
+		entries []Entry
+		// nil if robots.txt shouldn't be generated
+		expected []byte
+	}{
+		{
+			siteEntries(baseURLConfig, feedTestFiles),
+			[]byte("User-agent: *\nAllow: /\n\nSitemap: https://example.com/sitemap.xml\n"),
+		},
+		{
+			addFileEntries(
+				siteEntries(baseURLConfig, feedTestFiles),
+				map[string]string{"static/robots.txt": "mine"},
+			),
+			nil,
+		},
+		{
+			addFileEntries(
+				siteEntries(baseURLConfig, feedTestFiles),
+				map[string]string{"robots.txt": "mine"},
+			),
+			[]byte("mine"),
+		},
+		{siteEntries("", nil), nil},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			s, err := BuildFromEntries(tt.entries, BuildOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			robots := findNode(s.Nodes, "robots.txt")
+			if tt.expected == nil {
+				if robots != nil {
+					t.Errorf("robots.txt shouldn't be generated. got=\n%s", robots.Content)
+				}
+				return
+			}
+			if robots == nil {
+				t.Fatal("robots.txt wasn't generated")
+			}
+			if string(robots.Content) != string(tt.expected) {
+				t.Errorf("wrong robots.txt. expected=%q got=%q", tt.expected, robots.Content)
+			}
+		})
+	}
+}
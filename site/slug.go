@@ -0,0 +1,28 @@
+package site
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify converts s into a string that can safely be used as a file name
+// and url. letters and digits are lowercased and kept, every other run of
+// characters is replaced with a single '-'.
+func Slugify(s string) string {
+	var b strings.Builder
+	pendingDash := false
+
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		pendingDash = true
+	}
+
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package site
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"post", "post"},
+		{"My Post", "my-post"},
+		{"  My   Post  ", "my-post"},
+		{"Hello, World!", "hello-world"},
+		{"go-ssg: a static site generator", "go-ssg-a-static-site-generator"},
+		{"devlog #12", "devlog-12"},
+		{"a = b", "a-b"},
+		{"---", ""},
+		{"Ünïcode Títle", "ünïcode-títle"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			slug := Slugify(tt.input)
+			if slug != tt.expected {
+				t.Errorf(
+					"wrong slug. expected=%q. got=%q",
+					tt.expected,
+					slug,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package site
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// the taxonomies of a site that doesn't set taxonomies in ssg.toml
+var defaultTaxonomies = []string{"tags", "categories"}
+
+// Term is a value of a taxonomy. e.g. the go tag
+type Term struct {
+	Name string
+	// Pages are the pages with the term, newest first
+	Pages        []*PageContext
+	RelPermalink string
+	// Permalink is RelPermalink with the site's base_url
+	Permalink string
+}
+
+// Count is the number of pages with the term
+func (t Term) Count() int {
+	return len(t.Pages)
+}
+
+// taxonomies returns the taxonomies in ssg.toml or the default ones
+// if it doesn't set them
+func (config SiteConfig) taxonomies() []string {
+	if config.Taxonomies == nil {
+		return defaultTaxonomies
+	}
+	return config.Taxonomies
+}
+
+// taxonomies are directories of the site so their names have to be
+// usable in urls
+func validateTaxonomies(config SiteConfig) error {
+	for i, taxonomy := range config.Taxonomies {
+		if taxonomy == "" || Slugify(taxonomy) != taxonomy {
+			return fmt.Errorf(
+				"invalid taxonomy %q. use lowercase letters, digits and dashes",
+				taxonomy,
+			)
+		}
+		if slices.Contains(config.Taxonomies[:i], taxonomy) {
+			return fmt.Errorf("taxonomy %s is listed twice", taxonomy)
+		}
+	}
+	return nil
+}
+
+// Terms returns the terms of taxonomy in the page's front matter.
+// e.g. page.Terms("tags") is the same as page.Tags
+func (p *Page) Terms(taxonomy string) []string {
+	if taxonomy == "tags" {
+		return p.Tags
+	}
+	terms, _ := stringList(p.Params[taxonomy])
+	return terms
+}
+
+// validateTerms checks that every taxonomy in the front matter of page
+// is a list of strings. tags are checked by newPage
+func validateTerms(page *Page, taxonomies []string) error {
+	for _, taxonomy := range taxonomies {
+		value, ok := page.Params[taxonomy]
+		if !ok || taxonomy == "tags" {
+			continue
+		}
+		_, err := stringList(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", taxonomy, err)
+		}
+	}
+	return nil
+}
+
+// taxonomyTerms returns the terms of taxonomy sorted by name regardless of
+// case. terms that have the same url like Go and go are merged
+func taxonomyTerms(site *SiteContext, taxonomy string) []Term {
+	var terms []Term
+	bySlug := make(map[string]int)
+
+	for _, name := range slices.Sorted(maps.Keys(site.Taxonomies[taxonomy])) {
+		slug := Slugify(name)
+		if slug == "" {
+			continue
+		}
+
+		i, ok := bySlug[slug]
+		if !ok {
+			relPermalink := "/" + path.Join(taxonomy, slug) + "/"
+			terms = append(terms, Term{
+				Name:         name,
+				RelPermalink: relPermalink,
+				Permalink:    absURL(site.BaseURL, relPermalink),
+			})
+			i = len(terms) - 1
+			bySlug[slug] = i
+		}
+		terms[i].Pages = append(terms[i].Pages, site.Taxonomies[taxonomy][name]...)
+	}
+
+	slices.SortStableFunc(terms, func(a, b Term) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	for i := range terms {
+		pages := slices.Clone(terms[i].Pages)
+		slices.SortStableFunc(pages, func(a, b *PageContext) int {
+			if c := b.Date.Compare(a.Date); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.RelPermalink, b.RelPermalink)
+		})
+		terms[i].Pages = slices.CompactFunc(pages, func(a, b *PageContext) bool {
+			return a == b
+		})
+	}
+	return terms
+}
+
+// generateTaxonomies renders a page that lists the terms of every taxonomy
+// (e.g. tags/index.html) and a page for each term (e.g. tags/go/index.html).
+// terms also get an rss feed if the site has feeds
+func generateTaxonomies(site *SiteContext, l layouts) ([]Node, error) {
+	var nodes []Node
+	for _, taxonomy := range site.Config.taxonomies() {
+		terms := taxonomyTerms(site, taxonomy)
+		if len(terms) == 0 {
+			continue
+		}
+
+		relPermalink := "/" + taxonomy + "/"
+		ctx := Context{
+			Site: site,
+			Page: &PageContext{
+				Page:         &Page{Title: taxonomy},
+				RelPermalink: relPermalink,
+				Permalink:    absURL(site.BaseURL, relPermalink),
+				Section:      taxonomy,
+			},
+			Terms: terms,
+		}
+		content, err := renderLayout(l.taxonomy, ctx)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, Node{
+			Name:    path.Join(taxonomy, "index.html"),
+			Type:    HTMLNode,
+			Content: content,
+		})
+
+		for _, term := range terms {
+			termNodes, err := generateTerm(site, l, taxonomy, term)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, termNodes...)
+		}
+	}
+	return nodes, nil
+}
+
+func generateTerm(site *SiteContext, l layouts, taxonomy string, term Term) ([]Node, error) {
+	dir := strings.Trim(term.RelPermalink, "/")
+	f := feed{
+		title: site.Title + " - " + term.Name,
+		dir:   dir,
+		pages: feedPages(site.Config.Feed, term.Pages),
+	}
+
+	var feeds []FeedLink
+	if len(site.Feeds) > 0 {
+		feeds = []FeedLink{
+			{Type: "application/rss+xml", URL: f.url(site, rssFeedName)},
+		}
+	}
+
+	ctx := Context{
+		Site: site,
+		Page: &PageContext{
+			Page:         &Page{Title: term.Name},
+			RelPermalink: term.RelPermalink,
+			Permalink:    term.Permalink,
+			Section:      taxonomy,
+		},
+		Feeds: feeds,
+	}
+	nodes, err := renderList(l.term, ctx, term.Pages)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(feeds) > 0 {
+		rss, err := generateRSSFeed(site, f)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, Node{
+			Name:    path.Join(dir, rssFeedName),
+			Type:    FileNode,
+			Content: rss,
+		})
+	}
+	return nodes, nil
+}
+
+// addNode adds node to the tree of nodes and creates the directories it's
+// in. a node that's already in the tree isn't replaced so that the files of
+// the site are used over generated ones
+func addNode(nodes []Node, node Node) []Node {
+	return addNodeIn(nodes, "", node)
+}
+
+// parent is the name of the directory that nodes are in with a trailing
+// slash. empty at the root of the site
+func addNodeIn(nodes []Node, parent string, node Node) []Node {
+	dir, _, nested := strings.Cut(strings.TrimPrefix(node.Name, parent), "/")
+	if !nested {
+		if slices.ContainsFunc(nodes, func(n Node) bool { return n.Name == node.Name }) {
+			return nodes
+		}
+		return append(nodes, node)
+	}
+
+	name := parent + dir
+	for i := range nodes {
+		if nodes[i].Name != name {
+			continue
+		}
+		if nodes[i].Type == DirectoryNode {
+			nodes[i].Children = addNodeIn(nodes[i].Children, name+"/", node)
+		}
+		return nodes
+	}
+	return append(nodes, Node{
+		Name:     name,
+		Type:     DirectoryNode,
+		Children: addNodeIn(nil, name+"/", node),
+	})
+}
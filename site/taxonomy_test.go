@@ -0,0 +1,216 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// taxonomyTestFiles are the pages of the taxonomy tests
+var taxonomyTestFiles = map[string]string{
+	"content/a.md": "+++\ntitle = \"a\"\ndate = 2024-01-01\ntags = [\"go\", \"devlog\"]\ncategories = \"projects\"\n+++\n",
+	"content/b.md": "+++\ntitle = \"b\"\ndate = 2024-02-01\ntags = [\"Go\"]\nseries = [\"ssg\"]\n+++\n",
+	"content/c.md": "+++\ntitle = \"c\"\ndate = 2024-03-01\ntags = [\"web\"]\n+++\n",
+}
+
+func TestTaxonomies(t *testing.T) {
+	s, err := BuildFromEntries(siteEntries(baseURLConfig, taxonomyTestFiles), BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// strings that have to be in the node in this order
+		contains []string
+	}{
+		{
+			"tags/index.html",
+			[]string{
+				`<a class="term-item" href="/tags/devlog/">`, `devlog <span class="term-count">1</span>`,
+				`<a class="term-item" href="/tags/go/">`, `Go <span class="term-count">2</span>`,
+				`<a class="term-item" href="/tags/web/">`, `web <span class="term-count">1</span>`,
+			},
+		},
+		{
+			"tags/go/index.html",
+			[]string{
+				`<link rel="alternate" type="application/rss&#43;xml" title="Go" href="https://example.com/tags/go/feed.xml" />`,
+				`<h1>tags: Go</h1>`,
+				`href="/content/b/"`,
+				`href="/content/a/"`,
+			},
+		},
+		{
+			"tags/go/feed.xml",
+			[]string{
+				"<title>test blog - Go</title>",
+				"<link>https://example.com/tags/go/</link>",
+				`<atom:link href="https://example.com/tags/go/feed.xml" rel="self" type="application/rss+xml"></atom:link>`,
+				"<link>https://example.com/content/b/</link>",
+				"<link>https://example.com/content/a/</link>",
+			},
+		},
+		{"categories/index.html", []string{`href="/categories/projects/"`}},
+		{"categories/projects/index.html", []string{`href="/content/a/"`}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			node := findNode(s.Nodes, tt.name)
+			if node == nil {
+				t.Fatalf("%s wasn't generated", tt.name)
+			}
+			last := -1
+			for _, str := range tt.contains {
+				i := bytes.Index(node.Content, []byte(str))
+				if i <= last {
+					t.Fatalf("%s is missing or in the wrong order in %s\n%s", str, tt.name, node.Content)
+				}
+				last = i
+			}
+		})
+	}
+
+	if findNode(s.Nodes, "series") != nil {
+		t.Errorf("series isn't a taxonomy by default")
+	}
+	if bytes.Contains(findNode(s.Nodes, "tags/go/index.html").Content, []byte("/content/c/")) {
+		t.Errorf("c isn't tagged with go")
+	}
+}
+
+func TestTaxonomyConfig(t *testing.T) {
+	tests := []struct {
+		config string
+		// files that are added to the site
+		extra map[string]string
+		// nodes that have to exist
+		exist []string
+		// nodes that shouldn't exist
+		missing []string
+	}{
+		{
+			"taxonomies = [\"series\"]\n", nil,
+			[]string{"series/index.html", "series/ssg/index.html", "series/ssg/feed.xml"},
+			[]string{"tags", "categories"},
+		},
+		{"taxonomies = []\n", nil, nil, []string{"tags", "categories", "series"}},
+		{
+			"[feed]\nenabled = false\n", nil,
+			[]string{"tags/go/index.html"},
+			[]string{"tags/go/feed.xml"},
+		},
+		{
+			"",
+			map[string]string{"tags/index.html": "mine"},
+			[]string{"tags/go/index.html"},
+			nil,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			entries := addFileEntries(siteEntries(baseURLConfig+tt.config, taxonomyTestFiles), tt.extra)
+			s, err := BuildFromEntries(entries, BuildOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.exist {
+				if findNode(s.Nodes, name) == nil {
+					t.Errorf("%s wasn't generated", name)
+				}
+			}
+			for _, name := range tt.missing {
+				if findNode(s.Nodes, name) != nil {
+					t.Errorf("%s shouldn't be generated", name)
+				}
+			}
+			if tt.extra != nil {
+				index := findNode(s.Nodes, "tags/index.html")
+				if string(index.Content) != "mine" {
+					t.Errorf("the site's tags/index.html was replaced")
+				}
+			}
+		})
+	}
+}
+
+func TestTaxonomyErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		md     string
+		err    string
+	}{
+		{
+			"taxonomies = [\"Tags\"]\n", "",
+			`ssg.toml: error: failed to parse config: invalid taxonomy "Tags". use lowercase letters, digits and dashes`,
+		},
+		{
+			"taxonomies = [\"tags\", \"tags\"]\n", "",
+			"ssg.toml: error: failed to parse config: taxonomy tags is listed twice",
+		},
+		{
+			"", "+++\ntitle = \"a\"\ndate = 2024-01-01\ncategories = 1\n+++\n",
+			"content/bad.md: error: invalid categories: expected a list of strings. got 1",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			files := map[string]string{}
+			if tt.md != "" {
+				files["content/bad.md"] = tt.md
+			}
+			_, err := BuildFromEntries(siteEntries(tt.config, files), BuildOptions{})
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("wrong err. expected=%q. got=%q", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestTermLayout(t *testing.T) {
+	entries := addFileEntries(siteEntries(baseURLConfig, taxonomyTestFiles), map[string]string{
+		"layouts/term.html": `{{.Page.Title}}:{{range .Pages}} {{.Title}}{{end}}`,
+	})
+	s, err := BuildFromEntries(entries, BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	term := findNode(s.Nodes, "tags/go/index.html")
+	if string(term.Content) != "Go: b a" {
+		t.Errorf("wrong term page. got=%q", term.Content)
+	}
+}
+
+func TestAddNode(t *testing.T) {
+	nodes := []Node{
+		{Name: "index.html", Type: HTMLNode},
+		{Name: "tags", Type: DirectoryNode, Children: []Node{
+			{Name: "tags/index.html", Type: HTMLNode, Content: []byte("mine")},
+		}},
+	}
+	nodes = addNode(nodes, Node{Name: "tags/index.html", Content: []byte("generated")})
+	nodes = addNode(nodes, Node{Name: "tags/go/index.html"})
+	nodes = addNode(nodes, Node{Name: "categories/a/index.html"})
+
+	expected := []Node{
+		{Name: "index.html", Type: HTMLNode},
+		{Name: "tags", Type: DirectoryNode, Children: []Node{
+			{Name: "tags/index.html", Type: HTMLNode, Content: []byte("mine")},
+			{Name: "tags/go", Type: DirectoryNode, Children: []Node{
+				{Name: "tags/go/index.html"},
+			}},
+		}},
+		{Name: "categories", Type: DirectoryNode, Children: []Node{
+			{Name: "categories/a", Type: DirectoryNode, Children: []Node{
+				{Name: "categories/a/index.html"},
+			}},
+		}},
+	}
+	if !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("wrong nodes.\nexpected=%v\ngot=%v", expected, nodes)
+	}
+}
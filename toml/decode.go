@@ -0,0 +1,463 @@
+package toml
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeError is returned by Unmarshal when a value can't be stored in
+// the Go value it belongs to.
+type DecodeError struct {
+	Line   int
+	Column int
+	// Path is the key of the value. e.g. menu[2].weight
+	Path string
+	Msg  string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Msg)
+}
+
+// Unmarshal parses a TOML document and stores the result in the value
+// pointed to by v.
+//
+// tables are decoded into structs and maps with string keys and arrays
+// into slices and arrays. struct fields are matched with the name in their
+// toml tag, or with their field name ignoring case if they don't have one.
+// a field with the tag toml:"-" is ignored. keys that don't match a field
+// are ignored as well.
+//
+// fields whose key isn't in the document keep their value. if they are
+// zero and have a default tag, the default is parsed and used instead.
+// e.g. Port int `toml:"port" default:"8080"`
+//
+// types that implement encoding.TextUnmarshaler are decoded from strings
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("toml: Unmarshal expects a non nil pointer, got %T", v)
+	}
+
+	root, err := parse(data)
+	if err != nil {
+		return err
+	}
+
+	return decode(&node{line: 1, column: 1, value: root}, rv.Elem(), "")
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+func decode(n *node, v reflect.Value, path string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decode(n, v.Elem(), path)
+	}
+
+	if t, ok := n.value.(time.Time); ok && v.Type() == timeType {
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if s, ok := n.value.(string); ok && reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		u := v.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return decodeErrorf(n, path, "%s", err)
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return decodeErrorf(n, path, "can't decode into %s", v.Type())
+		}
+		v.Set(reflect.ValueOf(n.toAny()))
+
+	case reflect.String:
+		s, ok := n.value.(string)
+		if !ok {
+			return expected(n, path, "string")
+		}
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, ok := n.value.(bool)
+		if !ok {
+			return expected(n, path, "boolean")
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := n.value.(int64)
+		if !ok {
+			return expected(n, path, "integer")
+		}
+		if v.OverflowInt(i) {
+			return decodeErrorf(n, path, "%d doesn't fit in %s", i, v.Type())
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := n.value.(int64)
+		if !ok {
+			return expected(n, path, "integer")
+		}
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return decodeErrorf(n, path, "%d doesn't fit in %s", i, v.Type())
+		}
+		v.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		switch f := n.value.(type) {
+		case float64:
+			if v.Kind() == reflect.Float32 && !math.IsInf(f, 0) && v.OverflowFloat(f) {
+				return decodeErrorf(n, path, "%v doesn't fit in %s", f, v.Type())
+			}
+			v.SetFloat(f)
+		case int64:
+			v.SetFloat(float64(f))
+		default:
+			return expected(n, path, "float")
+		}
+
+	case reflect.Slice:
+		arr, ok := n.value.(*array)
+		if !ok {
+			return expected(n, path, "array")
+		}
+		s := reflect.MakeSlice(v.Type(), len(arr.items), len(arr.items))
+		for i, item := range arr.items {
+			err := decode(item, s.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+
+	case reflect.Array:
+		arr, ok := n.value.(*array)
+		if !ok {
+			return expected(n, path, "array")
+		}
+		if len(arr.items) != v.Len() {
+			return decodeErrorf(
+				n,
+				path,
+				"expected an array with %d elements, found %d",
+				v.Len(),
+				len(arr.items),
+			)
+		}
+		for i, item := range arr.items {
+			err := decode(item, v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		t, ok := n.value.(*table)
+		if !ok {
+			return expected(n, path, "table")
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return decodeErrorf(n, path, "can't decode into %s", v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(t.entries)))
+		}
+		for _, key := range t.keys {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			err := decode(t.entries[key], elem, joinPath(path, key))
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+
+	case reflect.Struct:
+		t, ok := n.value.(*table)
+		if !ok {
+			return expected(n, path, "table")
+		}
+		return decodeStruct(t, v, path)
+
+	default:
+		return decodeErrorf(n, path, "can't decode into %s", v.Type())
+	}
+
+	return nil
+}
+
+func decodeStruct(t *table, v reflect.Value, path string) error {
+	if err := setDefaults(v); err != nil {
+		return err
+	}
+
+	fields := structFields(v.Type())
+	for _, key := range t.keys {
+		field, ok := findField(fields, key)
+		if !ok {
+			continue
+		}
+		fv, err := fieldByIndex(v, field.index)
+		if err != nil {
+			return err
+		}
+		err = decode(t.entries[key], fv, joinPath(path, key))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return quoteKey(key)
+	}
+	return path + "." + quoteKey(key)
+}
+
+func expected(n *node, path, typ string) error {
+	return decodeErrorf(n, path, "expected %s, found %s", typ, typeName(n.value))
+}
+
+func decodeErrorf(n *node, path, format string, args ...any) error {
+	return &DecodeError{
+		Line:   n.line,
+		Column: n.column,
+		Path:   path,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func typeName(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case bool:
+		return "boolean"
+	case time.Time:
+		return "datetime"
+	case *array:
+		return "array"
+	case *table:
+		if v.kind == inlineTable {
+			return "inline table"
+		}
+		return "table"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+type field struct {
+	name string
+	// true if the name came from a toml tag
+	tagged    bool
+	omitEmpty bool
+	index     []int
+	def       string
+	hasDef    bool
+}
+
+// structFields returns the fields of typ that can be decoded, including the
+// fields of embedded structs without a tag
+func structFields(typ reflect.Type) []field {
+	var fields []field
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+
+		tag, hasTag := sf.Tag.Lookup("toml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for _, f := range structFields(embedded) {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		f := field{
+			name:   name,
+			tagged: hasTag && name != "",
+			index:  []int{i},
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "omitempty" {
+				f.omitEmpty = true
+			}
+		}
+		f.def, f.hasDef = sf.Tag.Lookup("default")
+
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func findField(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if !f.tagged && strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but allocates nil
+// embedded pointers
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf(
+						"toml: can't set embedded pointer to unexported struct %s",
+						v.Type().Elem(),
+					)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// setDefaults sets the zero fields of the struct v to the value of their
+// default tag. nested structs get their defaults as well
+func setDefaults(v reflect.Value) error {
+	for _, f := range structFields(v.Type()) {
+		fv, err := fieldByIndex(v, f.index)
+		if err != nil {
+			return err
+		}
+
+		if f.hasDef {
+			if fv.IsZero() {
+				err := setDefault(fv, f.def)
+				if err != nil {
+					return fmt.Errorf(
+						"toml: invalid default %q for %s.%s: %w",
+						f.def,
+						v.Type(),
+						f.name,
+						err,
+					)
+				}
+			}
+			continue
+		}
+
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			if err := setDefaults(fv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setDefault(v reflect.Value, def string) error {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t, err := parseDefaultTime(def)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(def))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(def)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(def, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(def, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(def, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return errors.New("defaults aren't supported for " + v.Type().String())
+	}
+	return nil
+}
+
+func parseDefaultTime(s string) (time.Time, error) {
+	doc, err := parse([]byte("t = " + s))
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, ok := doc.entries["t"].value.(time.Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s isn't a date", s)
+	}
+	return t, nil
+}
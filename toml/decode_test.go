@@ -0,0 +1,212 @@
+package toml
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testMenuItem struct {
+	Name   string `toml:"name"`
+	URL    string `toml:"url"`
+	Weight int    `toml:"weight" default:"10"`
+}
+
+type testFeed struct {
+	Limit   int  `toml:"limit" default:"20"`
+	Enabled bool `toml:"enabled" default:"true"`
+}
+
+type testBase struct {
+	BaseURL string `toml:"base_url"`
+}
+
+type testConfig struct {
+	testBase
+	Title     string
+	Author    *string             `toml:"author"`
+	Tags      []string            `toml:"tags"`
+	Menu      []testMenuItem      `toml:"menu"`
+	Params    map[string]any      `toml:"params"`
+	Social    map[string]string   `toml:"social"`
+	Feed      testFeed            `toml:"feed"`
+	Published time.Time           `toml:"published"`
+	Ratio     float64             `toml:"ratio"`
+	Port      uint16              `toml:"port" default:"8080"`
+	Timezone  string              `toml:"timezone" default:"UTC"`
+	Addr      netip.Addr          `toml:"addr"`
+	Point     [2]int              `toml:"point"`
+	Skipped   string              `toml:"-"`
+	Nested    map[string][]string `toml:"nested"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	author := "Hassan"
+
+	tests := []struct {
+		input    string
+		expected testConfig
+		err      error
+	}{
+		{
+			"",
+			testConfig{
+				Feed:     testFeed{20, true},
+				Port:     8080,
+				Timezone: "UTC",
+			},
+			nil,
+		},
+		{
+			`
+base_url = "https://example.com"
+title = "blog"
+author = "Hassan"
+tags = ["go", "web"]
+published = 2024-01-02T03:04:05Z
+ratio = 1
+port = 3000
+addr = "127.0.0.1"
+point = [1, 2]
+Skipped = "nope"
+unknown = "ignored"
+
+[params]
+color = "red"
+size = 2
+
+[social]
+github = "https://github.com"
+
+[feed]
+enabled = false
+
+[nested]
+a = ["b", "c"]
+
+[[menu]]
+name = "Home"
+url = "/"
+weight = 1
+
+[[menu]]
+name = "About"
+url = "/about.html"
+`,
+			testConfig{
+				testBase:  testBase{"https://example.com"},
+				Title:     "blog",
+				Author:    &author,
+				Tags:      []string{"go", "web"},
+				Published: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Ratio:     1,
+				Port:      3000,
+				Timezone:  "UTC",
+				Addr:      netip.MustParseAddr("127.0.0.1"),
+				Point:     [2]int{1, 2},
+				Params:    map[string]any{"color": "red", "size": int64(2)},
+				Social:    map[string]string{"github": "https://github.com"},
+				Feed:      testFeed{20, false},
+				Nested:    map[string][]string{"a": {"b", "c"}},
+				Menu: []testMenuItem{
+					{"Home", "/", 1},
+					{"About", "/about.html", 10},
+				},
+			},
+			nil,
+		},
+		{
+			`title = 1`,
+			testConfig{},
+			fmt.Errorf("line 1: title: expected string, found integer"),
+		},
+		{
+			`
+[[menu]]
+name = "a"
+[[menu]]
+name = "b"
+[[menu]]
+name = "c"
+weight = "heavy"
+`,
+			testConfig{},
+			fmt.Errorf("line 8: menu[2].weight: expected integer, found string"),
+		},
+		{
+			`port = 70000`,
+			testConfig{},
+			fmt.Errorf("line 1: port: 70000 doesn't fit in uint16"),
+		},
+		{
+			`tags = ["a", 1]`,
+			testConfig{},
+			fmt.Errorf("line 1: tags[1]: expected string, found integer"),
+		},
+		{
+			`feed = "yes"`,
+			testConfig{},
+			fmt.Errorf("line 1: feed: expected table, found string"),
+		},
+		{
+			`addr = "not an ip"`,
+			testConfig{},
+			fmt.Errorf(`line 1: addr: ParseAddr("not an ip"): unable to parse IP`),
+		},
+		{
+			`point = [1, 2, 3]`,
+			testConfig{},
+			fmt.Errorf("line 1: point: expected an array with 2 elements, found 3"),
+		},
+		{
+			`title = `,
+			testConfig{},
+			fmt.Errorf("error on line 1: expected a value"),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			var config testConfig
+			err := Unmarshal([]byte(tt.input), &config)
+			if !errEqual(tt.err, err) {
+				t.Fatalf(
+					"unexpected err value. expected=%v. got=%v",
+					tt.err,
+					err,
+				)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(tt.expected, config) {
+				t.Errorf(
+					"unexpected value.\nexpected=%+v\n     got=%+v",
+					tt.expected,
+					config,
+				)
+			}
+		})
+	}
+}
+
+func TestUnmarshalKeepsExistingValues(t *testing.T) {
+	config := testConfig{Title: "default title", Timezone: "Europe/London"}
+	err := Unmarshal([]byte(`author = "a"`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Title != "default title" || config.Timezone != "Europe/London" {
+		t.Errorf("existing values were overwritten. got=%+v", config)
+	}
+}
+
+func TestUnmarshalInvalidTarget(t *testing.T) {
+	var config testConfig
+	if err := Unmarshal([]byte(""), config); err == nil {
+		t.Errorf("expected an error when v isn't a pointer")
+	}
+}
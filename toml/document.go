@@ -0,0 +1,173 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Document is a parsed TOML document that can be edited without losing its
+// comments, formatting or the order of its keys.
+type Document struct {
+	data []byte
+	root *table
+}
+
+func ParseDocument(data []byte) (*Document, error) {
+	data = bytes.Clone(data)
+	root, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{data: data, root: root}, nil
+}
+
+// Bytes returns the document with every edit applied
+func (d *Document) Bytes() []byte {
+	return bytes.Clone(d.data)
+}
+
+// Get returns the value of key. key is written like it would be in a
+// document, so dotted keys look up values in tables. e.g. "params.color".
+// values have the same types as the ones returned by Parse
+func (d *Document) Get(key string) (any, bool) {
+	parts, err := parseKeyString(key)
+	if err != nil {
+		return nil, false
+	}
+
+	t := d.root
+	for i, part := range parts {
+		n, ok := t.entries[part.name]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return n.toAny(), true
+		}
+
+		next, ok := lastTable(n)
+		if !ok {
+			return nil, false
+		}
+		t = next
+	}
+	return nil, false
+}
+
+// Set changes the value of key to value. value is encoded like it would be
+// by Marshal, with structs and maps written as inline tables.
+//
+// an existing value is replaced where it is. a new key is added after the
+// last key of the table it belongs to. tables that are defined with a
+// header can't be replaced
+func (d *Document) Set(key string, value any) error {
+	parts, err := parseKeyString(key)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := encodeValue(reflect.ValueOf(value))
+	if err != nil {
+		return err
+	}
+
+	t := d.root
+	// the table whose lines new keys are added to and the number of key
+	// parts it takes to get to it
+	section, sectionDepth := d.root, 0
+	found := 0
+
+	for _, part := range parts[:len(parts)-1] {
+		n, ok := t.entries[part.name]
+		if !ok {
+			break
+		}
+
+		next, ok := lastTable(n)
+		if !ok {
+			return fmt.Errorf("toml: %s is not a table", joinKey(parts[:found+1]))
+		}
+		t = next
+		found++
+
+		if t.end != -1 {
+			section, sectionDepth = t, found
+		}
+	}
+
+	if found == len(parts)-1 {
+		if n, ok := t.entries[parts[found].name]; ok {
+			if n.end == 0 {
+				return fmt.Errorf("toml: can't replace the table %s", joinKey(parts))
+			}
+			return d.replace(n.start, n.end, encoded)
+		}
+	}
+
+	switch t.kind {
+	case inlineTable:
+		return fmt.Errorf(
+			"toml: can't add keys to the inline table %s",
+			joinKey(parts[:found]),
+		)
+	case implicitTable:
+		// the table only exists because of a header like [a.b] so it
+		// needs a header of its own
+		text := fmt.Sprintf(
+			"[%s]\n%s = %s\n",
+			joinKey(parts[:found]),
+			joinKey(parts[found:]),
+			encoded,
+		)
+		if len(d.data) > 0 {
+			if d.data[len(d.data)-1] != '\n' {
+				text = "\n" + text
+			}
+			text = "\n" + text
+		}
+		return d.replace(len(d.data), len(d.data), text)
+	}
+
+	text := fmt.Sprintf("%s = %s\n", joinKey(parts[sectionDepth:]), encoded)
+	if section.end > 0 && d.data[section.end-1] != '\n' {
+		text = "\n" + text
+	}
+	return d.replace(section.end, section.end, text)
+}
+
+// replace replaces data[start:end] with text and parses the result again
+func (d *Document) replace(start, end int, text string) error {
+	data := slices.Concat(d.data[:start], []byte(text), d.data[end:])
+	root, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("toml: edit made the document invalid: %w", err)
+	}
+	d.data = data
+	d.root = root
+	return nil
+}
+
+// lastTable returns the table n holds. for arrays of tables it returns the
+// last table
+func lastTable(n *node) (*table, bool) {
+	switch v := n.value.(type) {
+	case *table:
+		return v, true
+	case *array:
+		if v.tables {
+			return v.items[len(v.items)-1].value.(*table), true
+		}
+	}
+	return nil, false
+}
+
+func parseKeyString(key string) ([]keyPart, error) {
+	p := &parser{data: []byte(key), line: 1}
+	parts, err := p.parseKey()
+	if err != nil || !p.eof() {
+		return nil, fmt.Errorf("toml: invalid key %q", key)
+	}
+	return parts, nil
+}
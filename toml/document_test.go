@@ -0,0 +1,236 @@
+package toml
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDocumentSet(t *testing.T) {
+	doc := `# site settings
+title = "blog" # the title
+author = "Hassan"
+
+[params]
+# colors
+color = "red"
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "i" }
+`
+
+	tests := []struct {
+		input    string
+		key      string
+		value    any
+		expected string
+		err      error
+	}{
+		{
+			doc, "title", "new blog",
+			`# site settings
+title = "new blog" # the title
+author = "Hassan"
+
+[params]
+# colors
+color = "red"
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "i" }
+`,
+			nil,
+		},
+		{
+			doc, "draft", false,
+			`# site settings
+title = "blog" # the title
+author = "Hassan"
+draft = false
+
+[params]
+# colors
+color = "red"
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "i" }
+`,
+			nil,
+		},
+		{
+			doc, "params.size", 2,
+			`# site settings
+title = "blog" # the title
+author = "Hassan"
+
+[params]
+# colors
+color = "red"
+size = 2
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "i" }
+`,
+			nil,
+		},
+		{
+			doc, "menu.weight", 3,
+			`# site settings
+title = "blog" # the title
+author = "Hassan"
+
+[params]
+# colors
+color = "red"
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "i" }
+weight = 3
+`,
+			nil,
+		},
+		{
+			doc, "menu.info.icon", "home",
+			`# site settings
+title = "blog" # the title
+author = "Hassan"
+
+[params]
+# colors
+color = "red"
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "home" }
+`,
+			nil,
+		},
+		{
+			doc, "social.github", "gh",
+			`# site settings
+title = "blog" # the title
+author = "Hassan"
+social.github = "gh"
+
+[params]
+# colors
+color = "red"
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+info = { icon = "i" }
+`,
+			nil,
+		},
+		{
+			"a.b = 1\n[x]\n", "a.c", []int{1, 2},
+			"a.b = 1\na.c = [1, 2]\n[x]\n",
+			nil,
+		},
+		{
+			"[x]\na = 1", "tags", map[string]string{"a": "b"},
+			"tags = { a = \"b\" }\n[x]\na = 1",
+			nil,
+		},
+		{
+			"[x]\na = 1", "x.b", true,
+			"[x]\na = 1\nb = true\n",
+			nil,
+		},
+		{
+			"[a.b]\nc = 1\n", "a.d", 2,
+			"[a.b]\nc = 1\n\n[a]\nd = 2\n",
+			nil,
+		},
+		{"", "title", "blog", "title = \"blog\"\n", nil},
+		{doc, "params", "x", doc, fmt.Errorf("toml: can't replace the table params")},
+		{doc, "menu.info.new", 1, doc, fmt.Errorf("toml: can't add keys to the inline table menu.info")},
+		{doc, "title.x", 1, doc, fmt.Errorf("toml: title is not a table")},
+		{doc, "bad key", 1, doc, fmt.Errorf(`toml: invalid key "bad key"`)},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			d, err := ParseDocument([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.Set(tt.key, tt.value)
+			if !errEqual(tt.err, err) {
+				t.Fatalf(
+					"unexpected err value. expected=%v. got=%v",
+					tt.err,
+					err,
+				)
+			}
+
+			if out := string(d.Bytes()); out != tt.expected {
+				t.Errorf(
+					"unexpected document.\nexpected=\n%s\ngot=\n%s",
+					tt.expected,
+					out,
+				)
+			}
+		})
+	}
+}
+
+func TestDocumentGet(t *testing.T) {
+	d, err := ParseDocument([]byte("title = \"blog\"\n[params]\ncolor = \"red\"\n[[menu]]\nname = \"a\"\n[[menu]]\nname = \"b\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		expected any
+		ok       bool
+	}{
+		{"title", "blog", true},
+		{"params.color", "red", true},
+		{"menu.name", "b", true},
+		{"dne", nil, false},
+		{"title.x", nil, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			value, ok := d.Get(tt.key)
+			if ok != tt.ok || value != tt.expected {
+				t.Errorf(
+					"expected=%v, %t. got=%v, %t",
+					tt.expected,
+					tt.ok,
+					value,
+					ok,
+				)
+			}
+		})
+	}
+}
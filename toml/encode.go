@@ -0,0 +1,378 @@
+package toml
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal returns the TOML encoding of v.
+// see Encoder.Encode for how values are encoded
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type Encoder struct {
+	w io.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes v as a TOML document. v has to be a struct or a map with
+// string keys.
+//
+// struct fields are written in the order they are declared and map keys are
+// sorted, so the output is stable. keys with values are written before
+// tables and arrays of tables. struct fields use the same toml tags as
+// Unmarshal and a field with the omitempty option is skipped if it's
+// zero. nil pointers, interfaces, maps and slices are skipped because TOML
+// has no null value.
+//
+// types that implement encoding.TextMarshaler are written as strings
+func (e *Encoder) Encode(v any) error {
+	rv := indirect(reflect.ValueOf(v))
+	if !isTable(rv) {
+		return fmt.Errorf("toml: can only encode structs and maps, got %T", v)
+	}
+
+	var buf bytes.Buffer
+	err := encodeTable(&buf, rv, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(buf.Bytes())
+	return err
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// indirect follows pointers and interfaces.
+// returns an invalid value if one of them is nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isTable(v reflect.Value) bool {
+	if !v.IsValid() || v.Type() == timeType || v.Type().Implements(textMarshalerType) {
+		return false
+	}
+	return v.Kind() == reflect.Struct || v.Kind() == reflect.Map
+}
+
+func isArrayOfTables(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false
+	}
+	if v.Len() == 0 {
+		return false
+	}
+	for i := 0; i < v.Len(); i++ {
+		if !isTable(indirect(v.Index(i))) {
+			return false
+		}
+	}
+	return true
+}
+
+type tableEntry struct {
+	key   string
+	value reflect.Value
+}
+
+// tableEntries returns the keys and values of a struct or map in the order
+// they are written
+func tableEntries(v reflect.Value) ([]tableEntry, error) {
+	var entries []tableEntry
+
+	if v.Kind() == reflect.Map {
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("toml: can't encode %s. map keys have to be strings", v.Type())
+		}
+
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, key := range keys {
+			value := indirect(v.MapIndex(key))
+			if !value.IsValid() || isNil(value) {
+				continue
+			}
+			entries = append(entries, tableEntry{key.String(), value})
+		}
+		return entries, nil
+	}
+
+	for _, f := range structFields(v.Type()) {
+		fv, ok := fieldByIndexNoAlloc(v, f.index)
+		if !ok {
+			continue
+		}
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		fv = indirect(fv)
+		if !fv.IsValid() || isNil(fv) {
+			continue
+		}
+		entries = append(entries, tableEntry{f.name, fv})
+	}
+	return entries, nil
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// returns false if one of the embedded structs is a nil pointer
+func fieldByIndexNoAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func encodeTable(buf *bytes.Buffer, v reflect.Value, path []string) error {
+	entries, err := tableEntries(v)
+	if err != nil {
+		return err
+	}
+
+	var tables, arrays []tableEntry
+	for _, entry := range entries {
+		switch {
+		case isTable(entry.value):
+			tables = append(tables, entry)
+		case isArrayOfTables(entry.value):
+			arrays = append(arrays, entry)
+		default:
+			value, err := encodeValue(entry.value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(buf, "%s = %s\n", quoteKey(entry.key), value)
+		}
+	}
+
+	for _, entry := range tables {
+		childPath := append(path[:len(path):len(path)], entry.key)
+
+		// a header is only needed if the table has keys of its own
+		// or if it would otherwise be missing from the document
+		if hasValues(entry.value) || !hasSubTables(entry.value) {
+			if buf.Len() > 0 {
+				buf.WriteByte('\n')
+			}
+			fmt.Fprintf(buf, "[%s]\n", joinKeys(childPath))
+		}
+
+		err := encodeTable(buf, entry.value, childPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range arrays {
+		childPath := append(path[:len(path):len(path)], entry.key)
+		for i := 0; i < entry.value.Len(); i++ {
+			if buf.Len() > 0 {
+				buf.WriteByte('\n')
+			}
+			fmt.Fprintf(buf, "[[%s]]\n", joinKeys(childPath))
+
+			err := encodeTable(buf, indirect(entry.value.Index(i)), childPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasValues(v reflect.Value) bool {
+	entries, _ := tableEntries(v)
+	for _, entry := range entries {
+		if !isTable(entry.value) && !isArrayOfTables(entry.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSubTables(v reflect.Value) bool {
+	entries, _ := tableEntries(v)
+	return len(entries) > 0
+}
+
+func joinKeys(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = quoteKey(key)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// encodeValue returns v written as an inline value
+func encodeValue(v reflect.Value) (string, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", fmt.Errorf("toml: can't encode nil")
+	}
+
+	if v.Type() == timeType {
+		return formatTime(v.Interface().(time.Time)), nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("toml: failed to marshal %s: %w", v.Type(), err)
+		}
+		return quoteString(string(text)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return quoteString(v.String()), nil
+
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return "", fmt.Errorf("toml: %d doesn't fit in a 64 bit integer", v.Uint())
+		}
+		return strconv.FormatUint(v.Uint(), 10), nil
+
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(v.Float(), v.Type().Bits()), nil
+
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			item, err := encodeValue(v.Index(i))
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+
+	case reflect.Struct, reflect.Map:
+		entries, err := tableEntries(v)
+		if err != nil {
+			return "", err
+		}
+		if len(entries) == 0 {
+			return "{}", nil
+		}
+
+		items := make([]string, len(entries))
+		for i, entry := range entries {
+			value, err := encodeValue(entry.value)
+			if err != nil {
+				return "", err
+			}
+			items[i] = quoteKey(entry.key) + " = " + value
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	}
+
+	return "", fmt.Errorf("toml: can't encode %s", v.Type())
+}
+
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if isControl(r) {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func formatFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, bits)
+	// keep the value a float when it's read back
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+func formatTime(t time.Time) string {
+	switch t.Location() {
+	case LocalDate:
+		return t.Format(time.DateOnly)
+	case LocalDatetime:
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case LocalTime:
+		return t.Format("15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
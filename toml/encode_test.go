@@ -0,0 +1,177 @@
+package toml
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testEncodeParams struct {
+	Color string `toml:"color"`
+}
+
+type testEncodeConfig struct {
+	Title    string            `toml:"title"`
+	Draft    bool              `toml:"draft"`
+	Weight   int               `toml:"weight,omitempty"`
+	Ratio    float64           `toml:"ratio"`
+	Tags     []string          `toml:"tags"`
+	Date     time.Time         `toml:"date"`
+	Addr     netip.Addr        `toml:"addr"`
+	Author   *string           `toml:"author"`
+	Params   testEncodeParams  `toml:"params"`
+	Social   map[string]string `toml:"social"`
+	Menu     []testMenuItem    `toml:"menu"`
+	Internal string            `toml:"-"`
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		input    any
+		expected string
+		err      error
+	}{
+		{
+			map[string]any{
+				"title":  "blog",
+				"author": "Hassan",
+				"count":  3,
+				"nil":    nil,
+			},
+			"author = \"Hassan\"\ncount = 3\ntitle = \"blog\"\n",
+			nil,
+		},
+		{
+			testEncodeConfig{
+				Title:    "a \"quoted\"\ttitle\n",
+				Ratio:    2,
+				Tags:     []string{"go", "web"},
+				Date:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Addr:     netip.MustParseAddr("127.0.0.1"),
+				Params:   testEncodeParams{"red"},
+				Social:   map[string]string{"github": "gh", "b.c": "d"},
+				Internal: "hidden",
+				Menu: []testMenuItem{
+					{"Home", "/", 1},
+					{"About", "/about.html", 2},
+				},
+			},
+			`title = "a \"quoted\"\ttitle\n"
+draft = false
+ratio = 2.0
+tags = ["go", "web"]
+date = 2024-01-02T03:04:05Z
+addr = "127.0.0.1"
+
+[params]
+color = "red"
+
+[social]
+"b.c" = "d"
+github = "gh"
+
+[[menu]]
+name = "Home"
+url = "/"
+weight = 1
+
+[[menu]]
+name = "About"
+url = "/about.html"
+weight = 2
+`,
+			nil,
+		},
+		{
+			map[string]any{
+				"menu": map[string]any{
+					"main": []map[string]any{{"name": "Home"}},
+				},
+				"point":  map[string]int{"x": 1},
+				"points": []any{map[string]int{"x": 1}, 2},
+				"floats": []float64{math.Inf(-1), 1e21, 0.5},
+				"local": []time.Time{
+					time.Date(2024, 1, 2, 0, 0, 0, 0, LocalDate),
+					time.Date(2024, 1, 2, 3, 4, 5, 0, LocalDatetime),
+					time.Date(0, 1, 1, 3, 4, 5, 0, LocalTime),
+				},
+				"empty": map[string]any{},
+			},
+			`floats = [-inf, 1e+21, 0.5]
+local = [2024-01-02, 2024-01-02T03:04:05, 03:04:05]
+points = [{ x = 1 }, 2]
+
+[empty]
+
+[[menu.main]]
+name = "Home"
+
+[point]
+x = 1
+`,
+			nil,
+		},
+		{"not a table", "", fmt.Errorf("toml: can only encode structs and maps, got string")},
+		{map[int]string{1: "a"}, "", fmt.Errorf("toml: can't encode map[int]string. map keys have to be strings")},
+		{map[string]any{"f": func() {}}, "", fmt.Errorf("toml: can't encode func()")},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			out, err := Marshal(tt.input)
+			if !errEqual(tt.err, err) {
+				t.Fatalf(
+					"unexpected err value. expected=%v. got=%v",
+					tt.err,
+					err,
+				)
+			}
+			if string(out) != tt.expected {
+				t.Errorf(
+					"unexpected output.\nexpected=\n%s\ngot=\n%s",
+					tt.expected,
+					out,
+				)
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	author := "Hassan"
+	config := testEncodeConfig{
+		Title:  "blog",
+		Draft:  true,
+		Weight: 3,
+		Ratio:  0.25,
+		Tags:   []string{"a"},
+		Date:   time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("", 60*60)),
+		Addr:   netip.MustParseAddr("::1"),
+		Author: &author,
+		Params: testEncodeParams{"blue"},
+		Social: map[string]string{"mastodon": "@a@b.c"},
+		Menu:   []testMenuItem{{"Home", "/", 1}},
+	}
+
+	out, err := Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded testEncodeConfig
+	err = Unmarshal(out, &decoded)
+	if err != nil {
+		t.Fatalf("failed to decode\n%s\n%v", out, err)
+	}
+
+	if !decoded.Date.Equal(config.Date) {
+		t.Errorf("wrong date. expected=%v got=%v", config.Date, decoded.Date)
+	}
+	decoded.Date = config.Date
+	if !reflect.DeepEqual(config, decoded) {
+		t.Errorf("round trip failed.\nexpected=%+v\n     got=%+v", config, decoded)
+	}
+}
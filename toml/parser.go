@@ -0,0 +1,1202 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type tableKind int
+
+const (
+	// created by a header like [a.b] for the table a
+	implicitTable tableKind = iota
+	// defined by a [header]
+	headerTable
+	// created by a dotted key like a.b = 1
+	dottedTable
+	// defined with { }. can't be extended
+	inlineTable
+	// the top level table
+	rootTable
+)
+
+type table struct {
+	kind tableKind
+	// keys in the order they were defined
+	keys    []string
+	entries map[string]*node
+	// the offset after the last line that belongs to the table.
+	// only set for the root table and tables defined by a header,
+	// -1 otherwise
+	end int
+}
+
+func newTable(kind tableKind) *table {
+	return &table{kind: kind, entries: make(map[string]*node), end: -1}
+}
+
+func (t *table) set(key string, n *node) {
+	t.keys = append(t.keys, key)
+	t.entries[key] = n
+}
+
+type array struct {
+	// arrays of tables are created with [[header]] and can be appended to
+	tables bool
+	items  []*node
+}
+
+// node is a value in a parsed document with its position.
+type node struct {
+	line   int
+	column int
+	// the offsets of the value in the document. both are 0 for tables
+	// that aren't written as a value
+	start int
+	end   int
+	// string, int64, float64, bool, time.Time, *array or *table
+	value any
+}
+
+// toAny converts n into the types returned by Parse
+func (n *node) toAny() any {
+	switch v := n.value.(type) {
+	case *table:
+		m := make(map[string]any, len(v.entries))
+		for k, child := range v.entries {
+			m[k] = child.toAny()
+		}
+		return m
+	case *array:
+		s := make([]any, len(v.items))
+		for i, item := range v.items {
+			s[i] = item.toAny()
+		}
+		return s
+	}
+	return n.value
+}
+
+type parser struct {
+	data      []byte
+	pos       int
+	line      int
+	lineStart int
+
+	root *table
+	// the table that key/value pairs are currently added to
+	current *table
+}
+
+func parse(data []byte) (*table, error) {
+	p := &parser{
+		data: data,
+		line: 1,
+		root: newTable(rootTable),
+	}
+	p.current = p.root
+
+	if !utf8.Valid(data) {
+		for p.pos < len(data) {
+			r, size := utf8.DecodeRune(data[p.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return nil, p.errorf("invalid utf-8")
+			}
+			p.pos += size
+			if r == '\n' {
+				p.line++
+				p.lineStart = p.pos
+			}
+		}
+	}
+
+	// a byte order mark is allowed at the start
+	if bytes.HasPrefix(data, []byte("\uFEFF")) {
+		p.pos = len("\uFEFF")
+		p.lineStart = p.pos
+	}
+
+	for !p.eof() {
+		err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.root.end == -1 {
+		p.root.end = len(data)
+	}
+	return p.root, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line, column := p.position()
+	return &ParseError{
+		Line:   line,
+		Column: column,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) errorAt(line, column int, format string, args ...any) error {
+	return &ParseError{
+		Line:   line,
+		Column: column,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) position() (line, column int) {
+	return p.line, utf8.RuneCount(p.data[p.lineStart:p.pos]) + 1
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.data[p.pos:], []byte(s))
+}
+
+// advance moves n bytes forward. the skipped bytes can't contain newlines
+func (p *parser) advance(n int) {
+	p.pos += n
+}
+
+func (p *parser) skipWhitespace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// returns true if a newline was skipped
+func (p *parser) skipNewline() (bool, error) {
+	switch {
+	case p.hasPrefix("\n"):
+		p.pos++
+	case p.hasPrefix("\r\n"):
+		p.pos += 2
+	case p.hasPrefix("\r"):
+		return false, p.errorf("expected a newline after \\r")
+	default:
+		return false, nil
+	}
+	p.line++
+	p.lineStart = p.pos
+	return true, nil
+}
+
+func isControl(r rune) bool {
+	return (r < 0x20 && r != '\t') || r == 0x7f
+}
+
+func (p *parser) skipComment() error {
+	if p.peek() != '#' {
+		return nil
+	}
+	for !p.eof() && p.peek() != '\n' && !p.hasPrefix("\r\n") {
+		r, size := utf8.DecodeRune(p.data[p.pos:])
+		if isControl(r) {
+			return p.errorf("control character %U is not allowed in comments", r)
+		}
+		p.advance(size)
+	}
+	return nil
+}
+
+// skips whitespace, comments and newlines. used inside of arrays
+func (p *parser) skipWhitespaceAndComments() error {
+	for {
+		p.skipWhitespace()
+		if err := p.skipComment(); err != nil {
+			return err
+		}
+		ok, err := p.skipNewline()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseExpression() error {
+	p.skipWhitespace()
+
+	var err error
+	isEmpty := false
+	switch {
+	case p.eof(), p.peek() == '#', p.peek() == '\n', p.peek() == '\r':
+		isEmpty = true
+	case p.peek() == '[':
+		// keys added to the root table go before the first header
+		if p.root.end == -1 {
+			p.root.end = p.lineStart
+		}
+		err = p.parseTableHeader()
+	default:
+		err = p.parseKeyValue(p.current)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.skipWhitespace()
+	if err := p.skipComment(); err != nil {
+		return err
+	}
+
+	if !p.eof() {
+		ok, err := p.skipNewline()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return p.errorf("expected a newline, found %q", p.peekRune())
+		}
+	}
+
+	if !isEmpty {
+		p.current.end = p.pos
+	}
+	return nil
+}
+
+func (p *parser) peekRune() rune {
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return r
+}
+
+type keyPart struct {
+	name   string
+	line   int
+	column int
+}
+
+func joinKey(key []keyPart) string {
+	parts := make([]string, len(key))
+	for i, part := range key {
+		parts[i] = quoteKey(part.name)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteKey returns key as it would be written in a document
+func quoteKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for i := 0; i < len(key); i++ {
+		if !isBareKeyChar(key[i]) {
+			return quoteString(key)
+		}
+	}
+	return key
+}
+
+func isBareKeyChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9') ||
+		c == '_' || c == '-'
+}
+
+func (p *parser) parseKey() ([]keyPart, error) {
+	var key []keyPart
+	for {
+		p.skipWhitespace()
+		line, column := p.position()
+
+		var name string
+		switch c := p.peek(); {
+		case c == '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			name = s
+		case c == '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			name = s
+		case isBareKeyChar(c):
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			// bare keys are ascii only
+			if !p.eof() && p.peek() >= utf8.RuneSelf {
+				return nil, p.invalidKeyChar()
+			}
+			name = string(p.data[start:p.pos])
+		default:
+			if p.eof() || c == '\n' || c == '\r' {
+				return nil, p.errorf("expected a key")
+			}
+			return nil, p.invalidKeyChar()
+		}
+
+		key = append(key, keyPart{name, line, column})
+
+		p.skipWhitespace()
+		if p.peek() != '.' {
+			return key, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) invalidKeyChar() error {
+	return p.errorf(
+		"%q is not a valid character in a key. bare keys can only contain letters, digits, '_' and '-'",
+		p.peekRune(),
+	)
+}
+
+func (p *parser) parseKeyValue(t *table) error {
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	p.skipWhitespace()
+	if p.peek() != '=' {
+		return p.errorf("expected = after the key %s", joinKey(key))
+	}
+	p.pos++
+	p.skipWhitespace()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	return p.setKey(t, key, value)
+}
+
+// setKey adds value to t. the tables for every part of a dotted key but the
+// last are created if they don't exist
+func (p *parser) setKey(t *table, key []keyPart, value *node) error {
+	for i, part := range key[:len(key)-1] {
+		existing, ok := t.entries[part.name]
+		if !ok {
+			child := newTable(dottedTable)
+			t.set(part.name, &node{line: part.line, column: part.column, value: child})
+			t = child
+			continue
+		}
+
+		child, ok := existing.value.(*table)
+		if !ok || child.kind != dottedTable {
+			return p.errorAt(
+				part.line,
+				part.column,
+				"cannot add keys to %s. it is already defined",
+				joinKey(key[:i+1]),
+			)
+		}
+		t = child
+	}
+
+	last := key[len(key)-1]
+	if _, ok := t.entries[last.name]; ok {
+		return p.errorAt(
+			last.line,
+			last.column,
+			"duplicate key %s",
+			joinKey(key),
+		)
+	}
+	t.set(last.name, value)
+	return nil
+}
+
+func (p *parser) parseTableHeader() error {
+	line, column := p.position()
+
+	isArray := p.hasPrefix("[[")
+	if isArray {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	if isArray {
+		if !p.hasPrefix("]]") {
+			return p.errorf("expected ]] at the end of the array of tables")
+		}
+		p.pos += 2
+	} else {
+		if p.peek() != ']' {
+			return p.errorf("expected ] at the end of the table")
+		}
+		p.pos++
+	}
+
+	t := p.root
+	for i, part := range key[:len(key)-1] {
+		existing, ok := t.entries[part.name]
+		if !ok {
+			child := newTable(implicitTable)
+			t.set(part.name, &node{line: part.line, column: part.column, value: child})
+			t = child
+			continue
+		}
+
+		switch v := existing.value.(type) {
+		case *table:
+			if v.kind == inlineTable {
+				return p.errorAt(
+					part.line,
+					part.column,
+					"cannot add tables to the inline table %s",
+					joinKey(key[:i+1]),
+				)
+			}
+			t = v
+		case *array:
+			if !v.tables {
+				return p.errorAt(
+					part.line,
+					part.column,
+					"%s is an array, not a table",
+					joinKey(key[:i+1]),
+				)
+			}
+			t = v.items[len(v.items)-1].value.(*table)
+		default:
+			return p.errorAt(
+				part.line,
+				part.column,
+				"%s is already defined as a value",
+				joinKey(key[:i+1]),
+			)
+		}
+	}
+
+	last := key[len(key)-1]
+	existing, exists := t.entries[last.name]
+
+	if isArray {
+		newTbl := &node{line: line, column: column, value: newTable(headerTable)}
+		if !exists {
+			arr := &array{tables: true, items: []*node{newTbl}}
+			t.set(last.name, &node{line: line, column: column, value: arr})
+			p.current = newTbl.value.(*table)
+			return nil
+		}
+		arr, ok := existing.value.(*array)
+		if !ok || !arr.tables {
+			return p.errorAt(
+				line,
+				column,
+				"%s is already defined and isn't an array of tables",
+				joinKey(key),
+			)
+		}
+		arr.items = append(arr.items, newTbl)
+		p.current = newTbl.value.(*table)
+		return nil
+	}
+
+	if !exists {
+		child := newTable(headerTable)
+		t.set(last.name, &node{line: line, column: column, value: child})
+		p.current = child
+		return nil
+	}
+
+	child, ok := existing.value.(*table)
+	if !ok || child.kind != implicitTable {
+		return p.errorAt(line, column, "table %s is already defined", joinKey(key))
+	}
+	child.kind = headerTable
+	p.current = child
+	return nil
+}
+
+func (p *parser) parseValue() (*node, error) {
+	line, column := p.position()
+	start := p.pos
+
+	var value any
+	var err error
+
+	switch c := p.peek(); {
+	case p.eof():
+		return nil, p.errorf("expected a value")
+	case p.hasPrefix(`"""`):
+		value, err = p.parseMultilineBasicString()
+	case c == '"':
+		value, err = p.parseBasicString()
+	case p.hasPrefix("'''"):
+		value, err = p.parseMultilineLiteralString()
+	case c == '\'':
+		value, err = p.parseLiteralString()
+	case p.hasPrefix("true") && !p.hasBareCharAt(len("true")):
+		p.pos += len("true")
+		value = true
+	case p.hasPrefix("false") && !p.hasBareCharAt(len("false")):
+		p.pos += len("false")
+		value = false
+	case c == '[':
+		value, err = p.parseArray()
+	case c == '{':
+		value, err = p.parseInlineTable()
+	case c == '+' || c == '-' || isDigit(c),
+		p.hasPrefix("inf") && !p.hasBareCharAt(len("inf")),
+		p.hasPrefix("nan") && !p.hasBareCharAt(len("nan")):
+		value, err = p.parseNumberOrDate()
+	case isBareKeyChar(c):
+		start := p.pos
+		for !p.eof() && isBareKeyChar(p.peek()) {
+			p.pos++
+		}
+		return nil, p.errorAt(
+			line,
+			column,
+			"invalid value %s. strings have to be quoted",
+			p.data[start:p.pos],
+		)
+	default:
+		return nil, p.errorf("expected a value, found %q", p.peekRune())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &node{
+		line:   line,
+		column: column,
+		start:  start,
+		end:    p.pos,
+		value:  value,
+	}, nil
+}
+
+func (p *parser) hasBareCharAt(offset int) bool {
+	i := p.pos + offset
+	return i < len(p.data) && isBareKeyChar(p.data[i])
+}
+
+func (p *parser) parseBasicString() (string, error) {
+	p.pos++ // "
+
+	var s strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' || p.peek() == '\r' {
+			return "", p.errorf("unterminated string")
+		}
+
+		switch c := p.peek(); c {
+		case '"':
+			p.pos++
+			return s.String(), nil
+		case '\\':
+			r, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			s.WriteRune(r)
+		default:
+			r, size := utf8.DecodeRune(p.data[p.pos:])
+			if isControl(r) {
+				return "", p.errorf("control character %U must be escaped", r)
+			}
+			s.WriteRune(r)
+			p.advance(size)
+		}
+	}
+}
+
+func (p *parser) parseEscape() (rune, error) {
+	p.pos++ // \
+	if p.eof() {
+		return 0, p.errorf("unterminated escape sequence")
+	}
+
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		return '\b', nil
+	case 't':
+		return '\t', nil
+	case 'n':
+		return '\n', nil
+	case 'f':
+		return '\f', nil
+	case 'r':
+		return '\r', nil
+	case '"':
+		return '"', nil
+	case '\\':
+		return '\\', nil
+	case 'u', 'U':
+		length := 4
+		if c == 'U' {
+			length = 8
+		}
+		if p.pos+length > len(p.data) {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		hex := string(p.data[p.pos : p.pos+length])
+		code, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return 0, p.errorf("invalid unicode escape \\%c%s", c, hex)
+		}
+		p.pos += length
+		return rune(code), nil
+	}
+	p.pos--
+	return 0, p.errorf("invalid escape sequence \\%c", p.peekRune())
+}
+
+func (p *parser) parseMultilineBasicString() (string, error) {
+	p.pos += 3 // """
+	if _, err := p.skipNewline(); err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+
+		switch c := p.peek(); {
+		case c == '"':
+			quotes := p.countQuotes('"')
+			if quotes < 3 {
+				s.WriteString(strings.Repeat(`"`, quotes))
+				p.pos += quotes
+				continue
+			}
+			if quotes > 5 {
+				return "", p.errorf("too many quotes at the end of a multi-line string")
+			}
+			s.WriteString(strings.Repeat(`"`, quotes-3))
+			p.pos += quotes
+			return s.String(), nil
+
+		case c == '\\' && p.isLineEndingBackslash():
+			p.pos++
+			for {
+				p.skipWhitespace()
+				ok, err := p.skipNewline()
+				if err != nil {
+					return "", err
+				}
+				if !ok {
+					break
+				}
+			}
+
+		case c == '\\':
+			r, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			s.WriteRune(r)
+
+		case c == '\n' || c == '\r':
+			if _, err := p.skipNewline(); err != nil {
+				return "", err
+			}
+			s.WriteByte('\n')
+
+		default:
+			r, size := utf8.DecodeRune(p.data[p.pos:])
+			if isControl(r) {
+				return "", p.errorf("control character %U must be escaped", r)
+			}
+			s.WriteRune(r)
+			p.advance(size)
+		}
+	}
+}
+
+// reports whether the backslash at p.pos is followed by whitespace and a newline
+func (p *parser) isLineEndingBackslash() bool {
+	i := p.pos + 1
+	for i < len(p.data) && (p.data[i] == ' ' || p.data[i] == '\t') {
+		i++
+	}
+	return i < len(p.data) && (p.data[i] == '\n' || p.data[i] == '\r')
+}
+
+func (p *parser) countQuotes(quote byte) int {
+	n := 0
+	for p.pos+n < len(p.data) && p.data[p.pos+n] == quote {
+		n++
+	}
+	return n
+}
+
+func (p *parser) parseLiteralString() (string, error) {
+	p.pos++ // '
+
+	start := p.pos
+	for {
+		if p.eof() || p.peek() == '\n' || p.peek() == '\r' {
+			return "", p.errorf("unterminated string")
+		}
+		if p.peek() == '\'' {
+			s := string(p.data[start:p.pos])
+			p.pos++
+			return s, nil
+		}
+
+		r, size := utf8.DecodeRune(p.data[p.pos:])
+		if isControl(r) {
+			return "", p.errorf("control character %U is not allowed in literal strings", r)
+		}
+		p.advance(size)
+	}
+}
+
+func (p *parser) parseMultilineLiteralString() (string, error) {
+	p.pos += 3 // '''
+	if _, err := p.skipNewline(); err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+
+		switch c := p.peek(); {
+		case c == '\'':
+			quotes := p.countQuotes('\'')
+			if quotes < 3 {
+				s.WriteString(strings.Repeat("'", quotes))
+				p.pos += quotes
+				continue
+			}
+			if quotes > 5 {
+				return "", p.errorf("too many quotes at the end of a multi-line string")
+			}
+			s.WriteString(strings.Repeat("'", quotes-3))
+			p.pos += quotes
+			return s.String(), nil
+
+		case c == '\n' || c == '\r':
+			if _, err := p.skipNewline(); err != nil {
+				return "", err
+			}
+			s.WriteByte('\n')
+
+		default:
+			r, size := utf8.DecodeRune(p.data[p.pos:])
+			if isControl(r) {
+				return "", p.errorf("control character %U is not allowed in literal strings", r)
+			}
+			s.WriteRune(r)
+			p.advance(size)
+		}
+	}
+}
+
+func (p *parser) parseArray() (*array, error) {
+	p.pos++ // [
+
+	arr := &array{}
+	for {
+		if err := p.skipWhitespaceAndComments(); err != nil {
+			return nil, err
+		}
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arr.items = append(arr.items, value)
+
+		if err := p.skipWhitespaceAndComments(); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return arr, nil
+		default:
+			if p.eof() {
+				return nil, p.errorf("unterminated array")
+			}
+			return nil, p.errorf("expected , or ] in array, found %q", p.peekRune())
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (*table, error) {
+	p.pos++ // {
+
+	t := newTable(inlineTable)
+
+	p.skipWhitespace()
+	if p.peek() == '}' {
+		p.pos++
+		return t, nil
+	}
+
+	for {
+		err := p.parseKeyValue(t)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipWhitespace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+			p.skipWhitespace()
+			if p.peek() == '}' {
+				return nil, p.errorf("trailing commas aren't allowed in inline tables")
+			}
+		case '}':
+			p.pos++
+			freezeTable(t)
+			return t, nil
+		default:
+			if p.eof() || p.peek() == '\n' || p.peek() == '\r' {
+				return nil, p.errorf("inline tables must be on a single line")
+			}
+			return nil, p.errorf("expected , or } in inline table, found %q", p.peekRune())
+		}
+	}
+}
+
+// tables defined with dotted keys in an inline table can't be extended either
+func freezeTable(t *table) {
+	t.kind = inlineTable
+	for _, n := range t.entries {
+		if child, ok := n.value.(*table); ok {
+			freezeTable(child)
+		}
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// matches the digits of a date or time like 0000-00-00 or 00:00
+func (p *parser) matchesPattern(offset int, pattern string) bool {
+	i := p.pos + offset
+	if i+len(pattern) > len(p.data) {
+		return false
+	}
+	for j := 0; j < len(pattern); j++ {
+		c := p.data[i+j]
+		if pattern[j] == '0' {
+			if !isDigit(c) {
+				return false
+			}
+		} else if c != pattern[j] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) parseNumberOrDate() (any, error) {
+	if p.matchesPattern(0, "0000-00-00") {
+		return p.parseDateTime()
+	}
+	if p.matchesPattern(0, "00:00") {
+		return p.parseLocalTime()
+	}
+
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if !isBareKeyChar(c) && c != '+' && c != '.' {
+			break
+		}
+		p.pos++
+	}
+	token := string(p.data[start:p.pos])
+
+	line, column := p.line, utf8.RuneCount(p.data[p.lineStart:start])+1
+	value, err := parseNumber(token)
+	if err != nil {
+		return nil, p.errorAt(line, column, "%s", err)
+	}
+	return value, nil
+}
+
+func parseNumber(token string) (any, error) {
+	unsigned := strings.TrimLeft(token, "+-")
+	sign := token[:len(token)-len(unsigned)]
+	if len(sign) > 1 {
+		return nil, fmt.Errorf("invalid number %s", token)
+	}
+
+	switch unsigned {
+	case "inf":
+		if sign == "-" {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+
+	if len(unsigned) > 2 && unsigned[0] == '0' {
+		base := 0
+		switch unsigned[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			if sign != "" {
+				return nil, fmt.Errorf("%s can't have a sign", token)
+			}
+			digits, ok := stripUnderscores(unsigned[2:], base)
+			if !ok {
+				return nil, fmt.Errorf("invalid number %s", token)
+			}
+			n, err := strconv.ParseUint(digits, base, 64)
+			if err != nil || n > math.MaxInt64 {
+				return nil, fmt.Errorf("%s doesn't fit in a 64 bit integer", token)
+			}
+			return int64(n), nil
+		}
+	}
+
+	if strings.ContainsAny(unsigned, ".eE") {
+		return parseFloat(token, sign, unsigned)
+	}
+
+	digits, ok := stripUnderscores(unsigned, 10)
+	if !ok || (len(digits) > 1 && digits[0] == '0') {
+		return nil, fmt.Errorf("invalid number %s", token)
+	}
+	n, err := strconv.ParseInt(sign+digits, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s doesn't fit in a 64 bit integer", token)
+	}
+	return n, nil
+}
+
+func parseFloat(token, sign, unsigned string) (float64, error) {
+	invalid := fmt.Errorf("invalid number %s", token)
+
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(unsigned), "e")
+	intPart, fracPart, hasFrac := strings.Cut(mantissa, ".")
+
+	intDigits, ok := stripUnderscores(intPart, 10)
+	if !ok || (len(intDigits) > 1 && intDigits[0] == '0') {
+		return 0, invalid
+	}
+
+	s := sign + intDigits
+	if hasFrac {
+		fracDigits, ok := stripUnderscores(fracPart, 10)
+		if !ok {
+			return 0, invalid
+		}
+		s += "." + fracDigits
+	}
+	if hasExponent {
+		expSign := ""
+		if len(exponent) > 0 && (exponent[0] == '+' || exponent[0] == '-') {
+			expSign = exponent[:1]
+			exponent = exponent[1:]
+		}
+		expDigits, ok := stripUnderscores(exponent, 10)
+		if !ok {
+			return 0, invalid
+		}
+		s += "e" + expSign + expDigits
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	return f, nil
+}
+
+// removes the underscores from s. every underscore has to be between two
+// digits. returns false if s isn't a valid number in base
+func stripUnderscores(s string, base int) (string, bool) {
+	if s == "" {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' {
+			if i == 0 || i == len(s)-1 || s[i-1] == '_' {
+				return "", false
+			}
+			continue
+		}
+		if !isDigitInBase(c, base) {
+			return "", false
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), true
+}
+
+func isDigitInBase(c byte, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return c >= '0' && c <= '7'
+	case 10:
+		return isDigit(c)
+	case 16:
+		return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+	}
+	return false
+}
+
+// LocalDatetime, LocalDate and LocalTime are the locations of the
+// time.Time values parsed from dates and times without an offset.
+var (
+	LocalDatetime = time.FixedZone("datetime-local", 0)
+	LocalDate     = time.FixedZone("date-local", 0)
+	LocalTime     = time.FixedZone("time-local", 0)
+)
+
+func (p *parser) parseDateTime() (time.Time, error) {
+	line, column := p.position()
+	invalid := func() (time.Time, error) {
+		return time.Time{}, p.errorAt(line, column, "invalid date %s", p.data[p.pos-10:p.pos])
+	}
+
+	year, _ := strconv.Atoi(string(p.data[p.pos : p.pos+4]))
+	month, _ := strconv.Atoi(string(p.data[p.pos+5 : p.pos+7]))
+	day, _ := strconv.Atoi(string(p.data[p.pos+8 : p.pos+10]))
+	p.pos += 10
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return invalid()
+	}
+
+	hasTime := false
+	switch p.peek() {
+	case 'T', 't':
+		hasTime = true
+	case ' ':
+		hasTime = p.matchesPattern(1, "00:")
+	}
+	if !hasTime {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, LocalDate), nil
+	}
+	p.pos++
+
+	clock, err := p.parseLocalTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := LocalDatetime
+	switch c := p.peek(); {
+	case c == 'Z' || c == 'z':
+		p.pos++
+		loc = time.UTC
+	case (c == '+' || c == '-') && p.matchesPattern(1, "00:00"):
+		hours, _ := strconv.Atoi(string(p.data[p.pos+1 : p.pos+3]))
+		minutes, _ := strconv.Atoi(string(p.data[p.pos+4 : p.pos+6]))
+		if hours > 23 || minutes > 59 {
+			return time.Time{}, p.errorf("invalid time zone offset")
+		}
+		offset := hours*60*60 + minutes*60
+		if c == '-' {
+			offset = -offset
+		}
+		p.pos += 6
+		loc = time.FixedZone("", offset)
+	}
+
+	return time.Date(
+		year,
+		time.Month(month),
+		day,
+		clock.Hour(),
+		clock.Minute(),
+		clock.Second(),
+		clock.Nanosecond(),
+		loc,
+	), nil
+}
+
+func (p *parser) parseLocalTime() (time.Time, error) {
+	line, column := p.position()
+	if !p.matchesPattern(0, "00:00:00") {
+		return time.Time{}, p.errorf("invalid time. expected hh:mm:ss")
+	}
+
+	hour, _ := strconv.Atoi(string(p.data[p.pos : p.pos+2]))
+	minute, _ := strconv.Atoi(string(p.data[p.pos+3 : p.pos+5]))
+	second, _ := strconv.Atoi(string(p.data[p.pos+6 : p.pos+8]))
+	p.pos += 8
+
+	if hour > 23 || minute > 59 || second > 60 {
+		return time.Time{}, p.errorAt(line, column, "invalid time %s", p.data[p.pos-8:p.pos])
+	}
+	// leap seconds can't be represented by time.Time
+	if second == 60 {
+		second = 59
+	}
+
+	nanosecond := 0
+	if p.peek() == '.' {
+		p.pos++
+		start := p.pos
+		for !p.eof() && isDigit(p.peek()) {
+			p.pos++
+		}
+		fraction := string(p.data[start:p.pos])
+		if fraction == "" {
+			return time.Time{}, p.errorf("expected digits after the decimal point")
+		}
+		// time.Time only has nanosecond precision
+		fraction = (fraction + "000000000")[:9]
+		nanosecond, _ = strconv.Atoi(fraction)
+	}
+
+	return time.Date(0, 1, 1, hour, minute, second, nanosecond, LocalTime), nil
+}
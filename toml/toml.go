@@ -1,100 +1,30 @@
+// Package toml parses TOML v1.0.0 documents.
 package toml
 
 import (
-	"bytes"
 	"fmt"
-	"unicode"
-	"unicode/utf8"
 )
 
-func Parse(toml []byte) (map[string]string, error) {
-	lines := bytes.Split(toml, []byte{'\n'})
-
-	res := make(map[string]string)
-
-	for i, line := range lines {
-		trimmed := bytes.TrimSpace(line)
-		if len(trimmed) == 0 {
-			continue
-		}
-
-		kv := bytes.Split(trimmed, []byte{'='})
-		if len(kv) != 2 {
-			return nil, fmt.Errorf(
-				"error on line %d. expected key = value",
-				i+1,
-			)
-		}
-
-		k, v, err := parseKeyValue(kv[0], kv[1])
-		if err != nil {
-			return nil, fmt.Errorf("error on line %d: %w", i+1, err)
-		}
-
-		res[string(k)] = string(v)
-	}
-
-	return res, nil
-}
-
-func parseKeyValue(key, value []byte) ([]byte, []byte, error) {
-	sanitizedKey, err := sanitizeKey(key)
-	if err != nil {
-		return nil, nil, fmt.Errorf(
-			"failed to sanitize key %s: %w",
-			string(bytes.TrimSpace(key)),
-			err,
-		)
-	}
-
-	sanitizedValue := bytes.TrimSpace(value)
-
-	switch sanitizedValue[0] {
-	case '\'':
-		sanitizedValue, err = stripCharacter(sanitizedValue, '\'')
-		if err != nil {
-			return nil, nil, err
-		}
-	case '"':
-		sanitizedValue, err = stripCharacter(sanitizedValue, '"')
-		if err != nil {
-			return nil, nil, err
-		}
-	default:
-		return nil, nil, fmt.Errorf("expected value to start with ' or \"")
-	}
-
-	return sanitizedKey, sanitizedValue, nil
+// ParseError is returned by Parse when the input isn't valid.
+// Line and Column start at 1. Column is 0 if it is unknown
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
 }
 
-func sanitizeKey(key []byte) ([]byte, error) {
-	trimmed := bytes.TrimSpace(key)
-
-	b := trimmed
-	for len(b) > 0 {
-		r, size := utf8.DecodeRune(b)
-		if r == utf8.RuneError && size == 1 {
-			return nil, fmt.Errorf("%c is not a valid utf8 character", r)
-		}
-
-		if !unicode.IsLetter(r) && r != '-' {
-			return nil, fmt.Errorf(
-				"%c is not a valid character. a key must only contain letters or a '-'",
-				r,
-			)
-		}
-		b = b[size:]
-	}
-
-	return trimmed, nil
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("error on line %d: %s", e.Line, e.Msg)
 }
 
-func stripCharacter(b []byte, ch byte) ([]byte, error) {
-	if b[len(b)-1] != ch {
-		return nil, fmt.Errorf(
-			"expected string to end with %c got=%c",
-			ch, b[len(b)-1],
-		)
+// Parse parses a TOML document. values are returned as one of
+// string, int64, float64, bool, time.Time, []any or map[string]any.
+// dates and times without an offset use the LocalDatetime, LocalDate and
+// LocalTime locations
+func Parse(toml []byte) (map[string]any, error) {
+	root, err := parse(toml)
+	if err != nil {
+		return nil, err
 	}
-	return b[1 : len(b)-1], nil
+	return (&node{value: root}).toAny().(map[string]any), nil
 }
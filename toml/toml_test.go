@@ -2,26 +2,30 @@ package toml
 
 import (
 	"fmt"
-	"maps"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestParse(t *testing.T) {
 	tests := []struct {
 		input    string
-		expected map[string]string
+		expected map[string]any
 		err      error
 	}{
-		{"", nil, nil},
-		{`theme = "rose-pine"`, map[string]string{"theme": "rose-pine"}, nil},
-		{`theme = 'rose-pine'`, map[string]string{"theme": "rose-pine"}, nil},
+		{"", map[string]any{}, nil},
+		{`theme = "rose-pine"`, map[string]any{"theme": "rose-pine"}, nil},
+		{`theme = 'rose-pine'`, map[string]any{"theme": "rose-pine"}, nil},
+		{`url = "https://example.com/?a=b"`, map[string]any{"url": "https://example.com/?a=b"}, nil},
+		{"under_score2 = 1", map[string]any{"under_score2": int64(1)}, nil},
 		{`
 title = "A Blog"
 theme = "rose-pine"
 deploy-to = "github"
 author = "Hassan"
 `,
-			map[string]string{
+			map[string]any{
 				"title":     "A Blog",
 				"theme":     "rose-pine",
 				"deploy-to": "github",
@@ -31,19 +35,306 @@ author = "Hassan"
 		{
 			`t'heme = 'rose-pine'`,
 			nil,
-			fmt.Errorf(
-				"error on line 1: failed to sanitize key t'heme: ' is not a valid character. a key must only contain letters or a '-'",
-			),
+			fmt.Errorf("error on line 1: expected = after the key t"),
 		},
 		{
 			`theme = ~rose-pine'`,
 			nil,
-			fmt.Errorf(`error on line 1: expected value to start with ' or "`),
+			fmt.Errorf(`error on line 1: expected a value, found '~'`),
 		},
 		{
 			`theme = rose-pine`,
 			nil,
-			fmt.Errorf(`error on line 1: expected value to start with ' or "`),
+			fmt.Errorf(`error on line 1: invalid value rose-pine. strings have to be quoted`),
+		},
+		// comments and whitespace
+		{
+			"# a comment\n\ttitle = \"blog\" # another comment\r\n\n",
+			map[string]any{"title": "blog"},
+			nil,
+		},
+		// strings
+		{
+			`a = "tab\there \"quoted\" \u00e9 \U0001F600 \\"`,
+			map[string]any{"a": "tab\there \"quoted\" é 😀 \\"},
+			nil,
+		},
+		{
+			`a = 'C:\Users\nodejs'`,
+			map[string]any{"a": `C:\Users\nodejs`},
+			nil,
+		},
+		{
+			"a = \"\"\"\nRoses are red\nViolets are blue\"\"\"",
+			map[string]any{"a": "Roses are red\nViolets are blue"},
+			nil,
+		},
+		{
+			"a = \"\"\"\nThe quick brown \\\n\n   fox jumps over \\\n   the lazy dog.\"\"\"",
+			map[string]any{"a": "The quick brown fox jumps over the lazy dog."},
+			nil,
+		},
+		{
+			`a = """Here are two quotation marks: "". Simple enough.""""`,
+			map[string]any{"a": `Here are two quotation marks: "". Simple enough."`},
+			nil,
+		},
+		{
+			"a = '''\nThe first newline is\ntrimmed in raw strings.\n   \\n is kept'''",
+			map[string]any{"a": "The first newline is\ntrimmed in raw strings.\n   \\n is kept"},
+			nil,
+		},
+		// integers
+		{
+			"a = +99\nb = -17\nc = 0\nd = 1_000\ne = 0xDEAD_beef\nf = 0o755\ng = 0b1101",
+			map[string]any{
+				"a": int64(99),
+				"b": int64(-17),
+				"c": int64(0),
+				"d": int64(1000),
+				"e": int64(0xdeadbeef),
+				"f": int64(0o755),
+				"g": int64(0b1101),
+			},
+			nil,
+		},
+		// floats
+		{
+			"a = 3.1415\nb = -0.01\nc = 5e+22\nd = 6.626e-34\ne = 224_617.445_991\nf = -inf\ng = +inf",
+			map[string]any{
+				"a": 3.1415,
+				"b": -0.01,
+				"c": 5e+22,
+				"d": 6.626e-34,
+				"e": 224617.445991,
+				"f": math.Inf(-1),
+				"g": math.Inf(1),
+			},
+			nil,
+		},
+		// booleans
+		{
+			"a = true\nb = false",
+			map[string]any{"a": true, "b": false},
+			nil,
+		},
+		// dates and times
+		{
+			"a = 1979-05-27T07:32:00Z\nb = 1979-05-27T00:32:00.999999-07:00\nc = 1979-05-27 07:32:00\nd = 1979-05-27\ne = 07:32:00.5",
+			map[string]any{
+				"a": time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+				"b": time.Date(1979, 5, 27, 0, 32, 0, 999999000, time.FixedZone("", -7*60*60)),
+				"c": time.Date(1979, 5, 27, 7, 32, 0, 0, LocalDatetime),
+				"d": time.Date(1979, 5, 27, 0, 0, 0, 0, LocalDate),
+				"e": time.Date(0, 1, 1, 7, 32, 0, 500000000, LocalTime),
+			},
+			nil,
+		},
+		// arrays
+		{
+			"a = [ 1, 2, 3 ]\nb = [\n  \"red\", # comment\n  'blue',\n]\nc = [ [ 1, 2 ], [\"a\", 1.5] ]\nd = []",
+			map[string]any{
+				"a": []any{int64(1), int64(2), int64(3)},
+				"b": []any{"red", "blue"},
+				"c": []any{[]any{int64(1), int64(2)}, []any{"a", 1.5}},
+				"d": []any{},
+			},
+			nil,
+		},
+		// keys
+		{
+			"\"127.0.0.1\" = \"value\"\n'quoted \"value\"' = 1\nphysical.color = \"orange\"\nsite . \"google.com\" = true\n1234 = 2",
+			map[string]any{
+				"127.0.0.1":      "value",
+				`quoted "value"`: int64(1),
+				"physical":       map[string]any{"color": "orange"},
+				"site":           map[string]any{"google.com": true},
+				"1234":           int64(2),
+			},
+			nil,
+		},
+		// tables
+		{
+			`title = "blog"
+
+[owner]
+name = "Tom"
+
+[menu.main]
+weight = 1
+
+[menu]
+enabled = true
+
+[fruit]
+apple.color = "red"
+
+[fruit.apple.texture]
+smooth = true
+`,
+			map[string]any{
+				"title": "blog",
+				"owner": map[string]any{"name": "Tom"},
+				"menu": map[string]any{
+					"main":    map[string]any{"weight": int64(1)},
+					"enabled": true,
+				},
+				"fruit": map[string]any{
+					"apple": map[string]any{
+						"color":   "red",
+						"texture": map[string]any{"smooth": true},
+					},
+				},
+			},
+			nil,
+		},
+		// inline tables
+		{
+			`name = { first = "Tom", last = "Preston-Werner" }
+point = {x=1,y=2}
+animal = { type.name = "pug" }
+empty = {}`,
+			map[string]any{
+				"name":   map[string]any{"first": "Tom", "last": "Preston-Werner"},
+				"point":  map[string]any{"x": int64(1), "y": int64(2)},
+				"animal": map[string]any{"type": map[string]any{"name": "pug"}},
+				"empty":  map[string]any{},
+			},
+			nil,
+		},
+		// arrays of tables
+		{
+			`[[menu.main]]
+name = "Home"
+url = "/"
+
+[[menu.main]]
+name = "About"
+
+[menu.main.params]
+icon = "info"
+
+[[menu.main]]
+`,
+			map[string]any{
+				"menu": map[string]any{
+					"main": []any{
+						map[string]any{"name": "Home", "url": "/"},
+						map[string]any{
+							"name":   "About",
+							"params": map[string]any{"icon": "info"},
+						},
+						map[string]any{},
+					},
+				},
+			},
+			nil,
+		},
+		// errors
+		{
+			"a = 1\na = 2",
+			nil,
+			fmt.Errorf("error on line 2: duplicate key a"),
+		},
+		{
+			"[a]\nb = 1\n\n[a]",
+			nil,
+			fmt.Errorf("error on line 4: table a is already defined"),
+		},
+		{
+			"[fruit]\napple.color = \"red\"\n[fruit.apple]",
+			nil,
+			fmt.Errorf("error on line 3: table fruit.apple is already defined"),
+		},
+		{
+			"[a.b.c]\nz = 9\n[a]\nb.c.t = 1",
+			nil,
+			fmt.Errorf("error on line 4: cannot add keys to b. it is already defined"),
+		},
+		{
+			"a = {b = 1}\n[a.c]",
+			nil,
+			fmt.Errorf("error on line 2: cannot add tables to the inline table a"),
+		},
+		{
+			"a = [1]\n[[a]]",
+			nil,
+			fmt.Errorf("error on line 2: a is already defined and isn't an array of tables"),
+		},
+		{
+			"a = {b = 1,}",
+			nil,
+			fmt.Errorf("error on line 1: trailing commas aren't allowed in inline tables"),
+		},
+		{
+			"a = {b = 1\n}",
+			nil,
+			fmt.Errorf("error on line 1: inline tables must be on a single line"),
+		},
+		{
+			"a = \"unterminated",
+			nil,
+			fmt.Errorf("error on line 1: unterminated string"),
+		},
+		{
+			`a = "\x"`,
+			nil,
+			fmt.Errorf(`error on line 1: invalid escape sequence \x`),
+		},
+		{
+			"a = 1 b = 2",
+			nil,
+			fmt.Errorf("error on line 1: expected a newline, found 'b'"),
+		},
+		{
+			"a = 012",
+			nil,
+			fmt.Errorf("error on line 1: invalid number 012"),
+		},
+		{
+			"a = 1__2",
+			nil,
+			fmt.Errorf("error on line 1: invalid number 1__2"),
+		},
+		{
+			"a = 9223372036854775808",
+			nil,
+			fmt.Errorf("error on line 1: 9223372036854775808 doesn't fit in a 64 bit integer"),
+		},
+		{
+			"a = 1.",
+			nil,
+			fmt.Errorf("error on line 1: invalid number 1."),
+		},
+		{
+			"a = 2000-02-30",
+			nil,
+			fmt.Errorf("error on line 1: invalid date 2000-02-30"),
+		},
+		{
+			"a = [1, 2",
+			nil,
+			fmt.Errorf("error on line 1: unterminated array"),
+		},
+		{
+			"[a\nb = 1",
+			nil,
+			fmt.Errorf("error on line 1: expected ] at the end of the table"),
+		},
+		{
+			"a =",
+			nil,
+			fmt.Errorf("error on line 1: expected a value"),
+		},
+		{
+			"a = \"b\"\r",
+			nil,
+			fmt.Errorf(`error on line 1: expected a newline after \r`),
+		},
+		{
+			"caf\u00e9 = 1",
+			nil,
+			fmt.Errorf(`error on line 1: 'é' is not a valid character in a key. bare keys can only contain letters, digits, '_' and '-'`),
 		},
 	}
 
@@ -58,7 +349,7 @@ author = "Hassan"
 				)
 			}
 
-			if !maps.Equal(tt.expected, parsed) {
+			if !reflect.DeepEqual(tt.expected, parsed) {
 				t.Errorf(
 					"unexpected parsed value. expected=%+v. got=%+v",
 					tt.expected,
@@ -69,6 +360,53 @@ author = "Hassan"
 	}
 }
 
+func TestParseNaN(t *testing.T) {
+	parsed, err := Parse([]byte("a = nan\nb = -nan"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a", "b"} {
+		f, ok := parsed[key].(float64)
+		if !ok || !math.IsNaN(f) {
+			t.Errorf("expected %s to be nan. got=%v", key, parsed[key])
+		}
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		input  string
+		line   int
+		column int
+	}{
+		{"a = 1\nb = 1\nb = 2", 3, 1},
+		{"title = \"blog\"\n\n  author = test", 3, 12},
+		{"a = \"\"\"\nmulti\nline\"\"\"\nb = é", 4, 5},
+		{"[table]\nkey = [\n  1,\n  ~\n]", 4, 3},
+		{"a = 1\nb = \"\xff\"", 2, 6},
+		{"a = 1\n\n\u00e9 = 2", 3, 1},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("expected a *ParseError. got=%T (%v)", err, err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.column {
+				t.Errorf(
+					"wrong position. expected=%d:%d got=%d:%d",
+					tt.line,
+					tt.column,
+					parseErr.Line,
+					parseErr.Column,
+				)
+			}
+		})
+	}
+}
+
 func errEqual(err1, err2 error) bool {
 	if err1 == nil && err2 == nil {
 		return true